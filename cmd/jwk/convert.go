@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ericyan/jwk"
+)
+
+func convert(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	to := fs.String("to", "", "output format: jwk, pem or der (default pem for JWK input, jwk otherwise)")
+	params := paramFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := readInput(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+
+	var key jwk.Key
+	if isJSON(data) {
+		if *to == "" {
+			*to = "pem"
+		}
+		key, err = jwk.Parse(data)
+	} else {
+		if *to == "" {
+			*to = "jwk"
+		}
+		key, err = parseX509(data, params)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch *to {
+	case "jwk":
+		return writeKey(stdout, key)
+	case "pem", "der":
+		blockType, der, err := marshalX509(key)
+		if err != nil {
+			return err
+		}
+		if *to == "der" {
+			_, err = stdout.Write(der)
+			return err
+		}
+		return pem.Encode(stdout, &pem.Block{Type: blockType, Bytes: der})
+	default:
+		return fmt.Errorf("unsupported output format %q", *to)
+	}
+}
+
+func isJSON(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+// parseX509 parses a PEM or DER encoded key or certificate.
+func parseX509(data []byte, params *jwk.Params) (jwk.Key, error) {
+	der := data
+	if block, _ := pem.Decode(data); block != nil {
+		der = block.Bytes
+	}
+
+	if cert, err := x509.ParseCertificate(der); err == nil {
+		sum := sha256.Sum256(cert.Raw)
+		params.X509SHA256Thumbprint = base64.RawURLEncoding.EncodeToString(sum[:])
+		return jwk.New(cert.PublicKey, params)
+	}
+
+	for _, parse := range []func([]byte) (interface{}, error){
+		x509.ParsePKCS8PrivateKey,
+		x509.ParsePKIXPublicKey,
+		func(der []byte) (interface{}, error) { return x509.ParseECPrivateKey(der) },
+		func(der []byte) (interface{}, error) { return x509.ParsePKCS1PrivateKey(der) },
+		func(der []byte) (interface{}, error) { return x509.ParsePKCS1PublicKey(der) },
+	} {
+		if k, err := parse(der); err == nil {
+			return jwk.New(k, params)
+		}
+	}
+
+	return nil, errors.New("unrecognized key format")
+}
+
+// marshalX509 encodes the key as PKCS #8 or PKIX, returning the PEM block
+// type along with the DER encoding.
+func marshalX509(key jwk.Key) (string, []byte, error) {
+	if key.Type() == jwk.TypeOCT {
+		return "", nil, errors.New("symmetric keys cannot be encoded as PEM or DER")
+	}
+
+	if key.IsPrivate() {
+		der, err := x509.MarshalPKCS8PrivateKey(key.CryptoKey())
+		return "PRIVATE KEY", der, err
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(key.CryptoKey())
+	return "PUBLIC KEY", der, err
+}
+
+// parseKeys parses data as either a single JWK or a JWK Set. In strict
+// mode, input accepted by the lenient parser but not by
+// jwk.ParseStrict is rejected.
+func parseKeys(data []byte, strict bool) (*jwk.Set, bool, error) {
+	var probe struct {
+		Keys json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, false, err
+	}
+
+	if strict {
+		if probe.Keys != nil {
+			set, err := jwk.ParseSetStrict(data)
+			return set, true, err
+		}
+		key, err := jwk.ParseStrict(data)
+		if err != nil {
+			return nil, false, err
+		}
+		return &jwk.Set{Keys: []jwk.Key{key}}, false, nil
+	}
+
+	if probe.Keys != nil {
+		set := new(jwk.Set)
+		if err := json.Unmarshal(data, set); err != nil {
+			return nil, false, err
+		}
+		return set, true, nil
+	}
+
+	key, err := jwk.Parse(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &jwk.Set{Keys: []jwk.Key{key}}, false, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ericyan/jwk"
+)
+
+func generate(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	kty := fs.String("kty", jwk.TypeEC, "key type: EC, RSA, OKP or oct")
+	crv := fs.String("crv", "", "curve for EC and OKP keys (default P-256 or Ed25519)")
+	bits := fs.Int("bits", 0, "key size for RSA and oct keys (default 2048 or 256)")
+	params := paramFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errUsage
+	}
+
+	ck, err := generateKey(*kty, *crv, *bits)
+	if err != nil {
+		return err
+	}
+
+	key, err := jwk.New(ck, params)
+	if err != nil {
+		return err
+	}
+	if key.ID() == "" {
+		tp, err := jwk.Thumbprint(key, crypto.SHA256)
+		if err != nil {
+			return err
+		}
+		key.Parameters().KeyID = base64.RawURLEncoding.EncodeToString(tp)
+	}
+
+	return writeKey(stdout, key)
+}
+
+func generateKey(kty, crv string, bits int) (jwk.CryptoKey, error) {
+	switch kty {
+	case jwk.TypeEC:
+		var curve elliptic.Curve
+		switch crv {
+		case "", "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", crv)
+		}
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	case jwk.TypeRSA:
+		if bits == 0 {
+			bits = 2048
+		}
+		if bits < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits")
+		}
+		return rsa.GenerateKey(rand.Reader, bits)
+	case jwk.TypeOKP:
+		if crv != "" && crv != jwk.CurveEd25519 {
+			return nil, fmt.Errorf("unsupported curve %q", crv)
+		}
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		return priv, err
+	case jwk.TypeOCT:
+		if bits == 0 {
+			bits = 256
+		}
+		if bits < 128 || bits%8 != 0 {
+			return nil, errors.New("oct keys must be a multiple of 8 bits and at least 128 bits")
+		}
+		k := make([]byte, bits/8)
+		_, err := rand.Read(k)
+		return k, err
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", kty)
+	}
+}
+
+// paramFlags registers the common key parameter flags on fs.
+func paramFlags(fs *flag.FlagSet) *jwk.Params {
+	params := new(jwk.Params)
+	fs.StringVar(&params.KeyID, "kid", "", "key ID")
+	fs.StringVar(&params.Algorithm, "alg", "", "intended algorithm")
+	fs.StringVar(&params.KeyUse, "use", "", "intended use: sig or enc")
+	return params
+}
+
+// writeKey writes key, including its private members, as indented JSON.
+func writeKey(w io.Writer, key jwk.Key) error {
+	data, err := jwk.MarshalPrivate(key)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(w, data)
+}
+
+func writeJSON(w io.Writer, data []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+
+	_, err := buf.WriteTo(w)
+	return err
+}
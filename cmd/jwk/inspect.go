@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ericyan/jwk"
+)
+
+var hashes = map[string]crypto.Hash{
+	"SHA-256": crypto.SHA256,
+	"SHA-384": crypto.SHA384,
+	"SHA-512": crypto.SHA512,
+}
+
+func thumbprint(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("thumbprint", flag.ContinueOnError)
+	hashName := fs.String("hash", "SHA-256", "hash function: SHA-256, SHA-384 or SHA-512")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	h, ok := hashes[*hashName]
+	if !ok {
+		return fmt.Errorf("unsupported hash function %q", *hashName)
+	}
+
+	data, err := readInput(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+
+	set, _, err := parseKeys(data, false)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range set.Keys {
+		tp, err := jwk.Thumbprint(key, h)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, base64.RawURLEncoding.EncodeToString(tp))
+	}
+
+	return nil
+}
+
+func public(args []string, stdin io.Reader, stdout io.Writer) error {
+	data, err := readInput(args, stdin)
+	if err != nil {
+		return err
+	}
+
+	set, isSet, err := parseKeys(data, false)
+	if err != nil {
+		return err
+	}
+
+	pub := set.Public()
+	if !isSet {
+		if len(pub.Keys) == 0 {
+			return errors.New("symmetric keys have no public part")
+		}
+		return writeKey(stdout, pub.Keys[0])
+	}
+
+	data, err = json.Marshal(pub)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(stdout, data)
+}
+
+func inspect(args []string, stdin io.Reader, stdout io.Writer) error {
+	data, err := readInput(args, stdin)
+	if err != nil {
+		return err
+	}
+
+	set, _, err := parseKeys(data, true)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	for i, key := range set.Keys {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		describe(w, key)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(data), "", "  "); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "\n%s\n", buf.Bytes())
+
+	return nil
+}
+
+// describe writes a summary of the key, without any secret material.
+func describe(w io.Writer, key jwk.Key) {
+	params := key.Parameters()
+
+	fmt.Fprintf(w, "kty:\t%s\n", key.Type())
+	if key.ID() != "" {
+		fmt.Fprintf(w, "kid:\t%s\n", key.ID())
+	}
+	if key.Alg() != "" {
+		fmt.Fprintf(w, "alg:\t%s\n", key.Alg())
+	}
+	if params.KeyUse != "" {
+		fmt.Fprintf(w, "use:\t%s\n", params.KeyUse)
+	}
+	if len(params.KeyOps) > 0 {
+		fmt.Fprintf(w, "key_ops:\t%v\n", params.KeyOps)
+	}
+	fmt.Fprintf(w, "private:\t%t\n", key.IsPrivate())
+	if size := keySize(key.CryptoKey()); size != "" {
+		fmt.Fprintf(w, "size:\t%s\n", size)
+	}
+	if tp, err := jwk.Thumbprint(key, crypto.SHA256); err == nil {
+		fmt.Fprintf(w, "thumbprint:\t%s\n", base64.RawURLEncoding.EncodeToString(tp))
+	}
+}
+
+func keySize(key jwk.CryptoKey) string {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return k.Curve.Params().Name
+	case *ecdsa.PrivateKey:
+		return k.Curve.Params().Name
+	case *rsa.PublicKey:
+		return fmt.Sprintf("%d bits", k.N.BitLen())
+	case *rsa.PrivateKey:
+		return fmt.Sprintf("%d bits", k.N.BitLen())
+	case ed25519.PublicKey, ed25519.PrivateKey:
+		return jwk.CurveEd25519
+	case []byte:
+		return fmt.Sprintf("%d bits", len(k)*8)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,89 @@
+// Command jwk generates, converts and inspects JSON Web Keys and manages
+// JWK Set files.
+//
+// Usage:
+//
+//	jwk generate [-kty EC|RSA|OKP|oct] [-crv curve] [-bits n] [-kid id] [-alg alg] [-use use]
+//	jwk convert [-to jwk|pem|der] [-kid id] [-alg alg] [-use use] [file]
+//	jwk thumbprint [-hash SHA-256|SHA-384|SHA-512] [file]
+//	jwk public [file]
+//	jwk inspect [file]
+//	jwk set list file
+//	jwk set add file [keyfile]
+//	jwk set remove file kid
+//
+// Keys are read from the named file, or from standard input if no file is
+// given, and written to standard output.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+var usage = `usage: jwk <command> [arguments]
+
+commands:
+  generate    generate a new key
+  convert     convert between JWK, PEM and DER
+  thumbprint  compute the RFC 7638 thumbprint of a key
+  public      strip private members from a key or key set
+  inspect     strictly validate and pretty-print a key or key set
+  set         list, add or remove keys in a JWK Set file
+`
+
+var errUsage = errors.New(usage)
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "jwk:", err)
+		if err == errUsage {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "generate":
+		return generate(args, stdout)
+	case "convert":
+		return convert(args, stdin, stdout)
+	case "thumbprint":
+		return thumbprint(args, stdin, stdout)
+	case "public":
+		return public(args, stdin, stdout)
+	case "inspect":
+		return inspect(args, stdin, stdout)
+	case "set":
+		return set(args, stdin, stdout)
+	default:
+		return errUsage
+	}
+}
+
+// readInput reads the file named by the only positional argument, or
+// stdin if there is none.
+func readInput(args []string, stdin io.Reader) ([]byte, error) {
+	switch len(args) {
+	case 0:
+		return io.ReadAll(stdin)
+	case 1:
+		return os.ReadFile(args[0])
+	default:
+		return nil, errUsage
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ericyan/jwk"
+)
+
+func runTest(t *testing.T, stdin []byte, args ...string) []byte {
+	t.Helper()
+
+	var stdout bytes.Buffer
+	if err := run(args, bytes.NewReader(stdin), &stdout); err != nil {
+		t.Fatalf("jwk %s: %s", strings.Join(args, " "), err)
+	}
+
+	return stdout.Bytes()
+}
+
+func TestGenerate(t *testing.T) {
+	for _, args := range [][]string{
+		{"-kty", "EC", "-crv", "P-384"},
+		{"-kty", "RSA", "-alg", "RS256"},
+		{"-kty", "OKP"},
+		{"-kty", "oct", "-bits", "512", "-kid", "foo"},
+	} {
+		out := runTest(t, nil, append([]string{"generate"}, args...)...)
+
+		key, err := jwk.Parse(out)
+		if err != nil {
+			t.Fatalf("failed to parse generated key %s: %s", out, err)
+		}
+		if !key.IsPrivate() || key.ID() == "" {
+			t.Errorf("unexpected generated key %s", out)
+		}
+	}
+
+	var stdout bytes.Buffer
+	if err := run([]string{"generate", "-kty", "RSA", "-bits", "1024"}, nil, &stdout); err == nil {
+		t.Error("expected error on generating weak RSA key")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	priv := runTest(t, nil, "generate", "-kty", "EC")
+
+	privPEM := runTest(t, priv, "convert")
+	if block, _ := pem.Decode(privPEM); block == nil || block.Type != "PRIVATE KEY" {
+		t.Fatalf("unexpected PEM output %s", privPEM)
+	}
+
+	roundTrip := runTest(t, privPEM, "convert", "-to", "jwk")
+	a, _ := jwk.Parse(priv)
+	b, err := jwk.Parse(roundTrip)
+	if err != nil || !jwk.Equal(a, b) {
+		t.Error("PEM round-trip gave different keys")
+	}
+
+	pub := runTest(t, priv, "public")
+	der := runTest(t, pub, "convert", "-to", "der")
+	c, err := jwk.Parse(runTest(t, der, "convert", "-kid", "foo"))
+	if err != nil || c.IsPrivate() || c.ID() != "foo" {
+		t.Error("unexpected key converted from DER")
+	}
+}
+
+func TestThumbprintCommand(t *testing.T) {
+	priv := runTest(t, nil, "generate", "-kty", "OKP")
+	pub := runTest(t, priv, "public")
+
+	a := runTest(t, priv, "thumbprint")
+	b := runTest(t, pub, "thumbprint")
+	if len(a) == 0 || !bytes.Equal(a, b) {
+		t.Error("private and public keys have different thumbprints")
+	}
+
+	key, _ := jwk.Parse(priv)
+	if strings.TrimSpace(string(a)) != key.ID() {
+		t.Error("default kid is not the thumbprint")
+	}
+}
+
+func TestInspect(t *testing.T) {
+	key := runTest(t, nil, "generate", "-kty", "RSA", "-kid", "foo", "-use", "sig")
+
+	out := string(runTest(t, key, "inspect"))
+	for _, s := range []string{"kid:", "foo", "use:", "sig", "2048 bits", "thumbprint:"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("inspect output missing %q", s)
+		}
+	}
+
+	var stdout bytes.Buffer
+	for _, data := range []string{
+		`{"kty":"EC"}`,
+		`{"kty":"oct","k":"AAAA","k":"AQAB"}`,
+	} {
+		if err := run([]string{"inspect"}, strings.NewReader(data), &stdout); err == nil {
+			t.Errorf("expected error on inspecting invalid key %s", data)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "jwks.json")
+
+	a := runTest(t, nil, "generate", "-kid", "a")
+	b := runTest(t, nil, "generate", "-kty", "oct", "-kid", "b")
+
+	runTest(t, a, "set", "add", file)
+	runTest(t, b, "set", "add", file)
+
+	var stdout bytes.Buffer
+	if err := run([]string{"set", "add", file}, bytes.NewReader(a), &stdout); err == nil {
+		t.Error("expected error on adding duplicate key")
+	}
+
+	out := string(runTest(t, nil, "set", "list", file))
+	if !strings.Contains(out, "a ") || !strings.Contains(out, "b ") {
+		t.Errorf("unexpected list output:\n%s", out)
+	}
+
+	runTest(t, nil, "set", "remove", file, "a")
+	if err := run([]string{"set", "remove", file, "a"}, nil, &stdout); err == nil {
+		t.Error("expected error on removing missing key")
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := new(jwk.Set)
+	if err := json.Unmarshal(data, set); err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 1 || set.Keys[0].ID() != "b" {
+		t.Error("unexpected set after removal")
+	}
+
+	pub := runTest(t, data, "public")
+	set = new(jwk.Set)
+	if err := json.Unmarshal(pub, set); err != nil || len(set.Keys) != 0 {
+		t.Error("public set contains symmetric key")
+	}
+}
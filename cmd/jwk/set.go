@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/ericyan/jwk"
+)
+
+func set(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	cmd, file, args := args[0], args[1], args[2:]
+	switch cmd {
+	case "list":
+		if len(args) != 0 {
+			return errUsage
+		}
+		return setList(file, stdout)
+	case "add":
+		data, err := readInput(args, stdin)
+		if err != nil {
+			return err
+		}
+		return setAdd(file, data)
+	case "remove":
+		if len(args) != 1 {
+			return errUsage
+		}
+		return setRemove(file, args[0])
+	default:
+		return errUsage
+	}
+}
+
+func setList(file string, stdout io.Writer) error {
+	set, err := readSet(file, false)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KID\tKTY\tALG\tUSE\tPRIVATE")
+	for _, key := range set.Keys {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n", key.ID(), key.Type(), key.Alg(), key.Parameters().KeyUse, key.IsPrivate())
+	}
+
+	return w.Flush()
+}
+
+func setAdd(file string, data []byte) error {
+	key, err := jwk.Parse(data)
+	if err != nil {
+		return err
+	}
+	if key.ID() == "" {
+		return errors.New("key has no kid")
+	}
+
+	set, err := readSet(file, true)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range set.Keys {
+		if k.ID() == key.ID() {
+			return fmt.Errorf("key %q already exists", key.ID())
+		}
+		if jwk.Equal(k, key) {
+			return fmt.Errorf("key already exists as %q", k.ID())
+		}
+	}
+	set.Keys = append(set.Keys, key)
+
+	return writeSet(file, set)
+}
+
+func setRemove(file, kid string) error {
+	set, err := readSet(file, false)
+	if err != nil {
+		return err
+	}
+
+	keys := set.Keys[:0]
+	for _, key := range set.Keys {
+		if key.ID() != kid {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == len(set.Keys) {
+		return fmt.Errorf("key %q not found", kid)
+	}
+	set.Keys = keys
+
+	return writeSet(file, set)
+}
+
+func readSet(file string, create bool) (*jwk.Set, error) {
+	data, err := os.ReadFile(file)
+	if create && errors.Is(err, fs.ErrNotExist) {
+		return &jwk.Set{Keys: []jwk.Key{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	set := new(jwk.Set)
+	if err := json.Unmarshal(data, set); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// writeSet replaces file atomically, so that readers never observe a
+// partially written set.
+func writeSet(file string, set *jwk.Set) error {
+	data, err := set.MarshalPrivate()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".jwks-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
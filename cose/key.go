@@ -0,0 +1,478 @@
+// Package cose converts between JSON Web Keys and COSE_Key structures as
+// defined in RFC 9052, Section 7 and RFC 9053, with RSA keys as defined
+// in RFC 8230.
+//
+// The kid, alg and key_ops parameters are carried across; parameters
+// without a COSE counterpart, such as use and x5t#S256, are dropped.
+package cose
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"math/big"
+
+	"github.com/ericyan/jwk"
+	"github.com/ericyan/jwk/internal/cbor"
+)
+
+// Key types defined in RFC 9053, Section 7 and RFC 8230, Section 4.
+const (
+	KeyTypeOKP       = 1
+	KeyTypeEC2       = 2
+	KeyTypeRSA       = 3
+	KeyTypeSymmetric = 4
+)
+
+// Elliptic curves defined in RFC 9053, Section 7.1.
+const (
+	CurveP256    = 1
+	CurveP384    = 2
+	CurveP521    = 3
+	CurveEd25519 = 6
+)
+
+// Common key parameters defined in RFC 9052, Section 7.1.
+const (
+	labelKeyType = 1
+	labelKeyID   = 2
+	labelAlg     = 3
+	labelKeyOps  = 4
+)
+
+// Key type parameters defined in RFC 9053, Section 7 and RFC 8230,
+// Section 4.
+const (
+	labelCurve = -1
+	labelX     = -2
+	labelY     = -3
+	labelD     = -4 // for EC2 and OKP keys
+
+	labelK = -1
+
+	labelN    = -1
+	labelE    = -2
+	labelRSAD = -3
+	labelP    = -4
+	labelQ    = -5
+	labelDP   = -6
+	labelDQ   = -7
+	labelQI   = -8
+	labelRSAO = -9
+)
+
+var curves = map[int64]ecdh.Curve{
+	CurveP256: ecdh.P256(),
+	CurveP384: ecdh.P384(),
+	CurveP521: ecdh.P521(),
+}
+
+var ellipticCurves = map[int64]elliptic.Curve{
+	CurveP256: elliptic.P256(),
+	CurveP384: elliptic.P384(),
+	CurveP521: elliptic.P521(),
+}
+
+// Marshal returns the CBOR encoding of the public part of key as a
+// COSE_Key, like json.Marshal does for JWKs. Symmetric keys have no
+// public part and must be marshaled with MarshalPrivate.
+func Marshal(key jwk.Key) ([]byte, error) {
+	if !key.IsPrivate() {
+		return MarshalPrivate(key)
+	}
+
+	pub, ok := key.(interface{ PublicKey() jwk.Key })
+	if !ok {
+		return nil, errors.New("cose: symmetric keys must be marshaled with MarshalPrivate")
+	}
+
+	return MarshalPrivate(pub.PublicKey())
+}
+
+// MarshalPrivate returns the CBOR encoding of key as a COSE_Key,
+// including private key material if the key holds it. Keys backed by an
+// opaque crypto.Signer are encoded as public keys.
+func MarshalPrivate(key jwk.Key) ([]byte, error) {
+	m := cbor.Map{}
+	if err := encodeKey(m, key.CryptoKey()); err != nil {
+		return nil, err
+	}
+
+	params := key.Parameters()
+	if params.KeyID != "" {
+		m[labelKeyID] = []byte(params.KeyID)
+	}
+	if params.Algorithm != "" {
+		if alg, ok := algorithmValue(params.Algorithm); ok {
+			m[labelAlg] = alg
+		} else {
+			m[labelAlg] = params.Algorithm
+		}
+	}
+	if len(params.KeyOps) > 0 {
+		ops := make([]interface{}, len(params.KeyOps))
+		for i, op := range params.KeyOps {
+			if v, ok := keyOpValue(op, key.Type() == jwk.TypeOCT); ok {
+				ops[i] = v
+			} else {
+				ops[i] = op
+			}
+		}
+		m[labelKeyOps] = ops
+	}
+
+	return cbor.Marshal(m)
+}
+
+func encodeKey(m cbor.Map, ck jwk.CryptoKey) error {
+	switch k := ck.(type) {
+	case *ecdsa.PublicKey:
+		crv, err := curveValue(k.Curve)
+		if err != nil {
+			return err
+		}
+		pub, err := k.ECDH()
+		if err != nil {
+			return errors.New("cose: invalid crypto key")
+		}
+		point := pub.Bytes()
+		size := (len(point) - 1) / 2
+
+		m[labelKeyType] = KeyTypeEC2
+		m[labelCurve] = crv
+		m[labelX] = point[1 : 1+size]
+		m[labelY] = point[1+size:]
+	case *ecdsa.PrivateKey:
+		if err := encodeKey(m, &k.PublicKey); err != nil {
+			return err
+		}
+		priv, err := k.ECDH()
+		if err != nil {
+			return errors.New("cose: invalid crypto key")
+		}
+		m[labelD] = priv.Bytes()
+	case *rsa.PublicKey:
+		m[labelKeyType] = KeyTypeRSA
+		m[labelN] = k.N.Bytes()
+		m[labelE] = big.NewInt(int64(k.E)).Bytes()
+	case *rsa.PrivateKey:
+		if len(k.Primes) != 2 {
+			return errors.New("cose: multi-prime RSA keys are not supported")
+		}
+		if err := encodeKey(m, &k.PublicKey); err != nil {
+			return err
+		}
+		if k.Precomputed.Dp == nil {
+			c := *k
+			c.Precompute()
+			k = &c
+		}
+		m[labelRSAD] = k.D.Bytes()
+		m[labelP] = k.Primes[0].Bytes()
+		m[labelQ] = k.Primes[1].Bytes()
+		m[labelDP] = k.Precomputed.Dp.Bytes()
+		m[labelDQ] = k.Precomputed.Dq.Bytes()
+		m[labelQI] = k.Precomputed.Qinv.Bytes()
+	case ed25519.PublicKey:
+		m[labelKeyType] = KeyTypeOKP
+		m[labelCurve] = CurveEd25519
+		m[labelX] = []byte(k)
+	case ed25519.PrivateKey:
+		if err := encodeKey(m, k.Public()); err != nil {
+			return err
+		}
+		m[labelD] = k.Seed()
+	case []byte:
+		m[labelKeyType] = KeyTypeSymmetric
+		m[labelK] = k
+	case crypto.Signer:
+		return encodeKey(m, k.Public())
+	default:
+		return errors.New("cose: unsupported key type")
+	}
+
+	return nil
+}
+
+func curveValue(curve elliptic.Curve) (int64, error) {
+	for crv, c := range ellipticCurves {
+		if c == curve {
+			return crv, nil
+		}
+	}
+
+	return 0, errors.New("cose: unsupported curve")
+}
+
+// Parse parses a CBOR-encoded COSE_Key as a JSON Web Key.
+func Parse(data []byte) (jwk.Key, error) {
+	key, rest, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("cose: trailing data after COSE_Key")
+	}
+
+	return key, nil
+}
+
+// Decode parses the CBOR-encoded COSE_Key at the beginning of data as a
+// JSON Web Key, and returns the remaining bytes.
+func Decode(data []byte) (jwk.Key, []byte, error) {
+	v, rest, err := cbor.Decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m, ok := v.(cbor.Map)
+	if !ok {
+		return nil, nil, errors.New("cose: COSE_Key is not a map")
+	}
+
+	params, err := decodeParams(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kty, ok := m[int64(labelKeyType)].(int64)
+	if !ok {
+		return nil, nil, errors.New("cose: missing or invalid kty")
+	}
+
+	var ck jwk.CryptoKey
+	switch kty {
+	case KeyTypeEC2:
+		ck, err = decodeEC2(m)
+	case KeyTypeOKP:
+		ck, err = decodeOKP(m)
+	case KeyTypeRSA:
+		ck, err = decodeRSA(m)
+	case KeyTypeSymmetric:
+		ck, err = bytesParam(m, labelK, true)
+	default:
+		err = errors.New("cose: unsupported key type")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := jwk.New(ck, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, rest, nil
+}
+
+func decodeParams(m cbor.Map) (*jwk.Params, error) {
+	params := new(jwk.Params)
+
+	if v, ok := m[int64(labelKeyID)]; ok {
+		kid, ok := v.([]byte)
+		if !ok {
+			return nil, errors.New("cose: invalid kid")
+		}
+		params.KeyID = string(kid)
+	}
+
+	switch alg := m[int64(labelAlg)].(type) {
+	case nil:
+	case int64:
+		name, ok := Algorithm(alg)
+		if !ok {
+			return nil, errors.New("cose: unsupported alg")
+		}
+		params.Algorithm = name
+	case string:
+		params.Algorithm = alg
+	default:
+		return nil, errors.New("cose: invalid alg")
+	}
+
+	if v, ok := m[int64(labelKeyOps)]; ok {
+		ops, ok := v.([]interface{})
+		if !ok || len(ops) == 0 {
+			return nil, errors.New("cose: invalid key_ops")
+		}
+		for _, op := range ops {
+			switch op := op.(type) {
+			case int64:
+				name, ok := keyOps[op]
+				if !ok {
+					return nil, errors.New("cose: unsupported key_ops value")
+				}
+				params.KeyOps = append(params.KeyOps, name)
+			case string:
+				params.KeyOps = append(params.KeyOps, op)
+			default:
+				return nil, errors.New("cose: invalid key_ops")
+			}
+		}
+	}
+
+	return params, nil
+}
+
+func decodeEC2(m cbor.Map) (jwk.CryptoKey, error) {
+	crv, _ := m[int64(labelCurve)].(int64)
+	curve, ok := curves[crv]
+	if !ok {
+		return nil, errors.New("cose: unsupported curve")
+	}
+
+	if _, ok := m[int64(labelY)].(bool); ok {
+		return nil, errors.New("cose: compressed points are not supported")
+	}
+
+	x, err := bytesParam(m, labelX, true)
+	if err != nil {
+		return nil, err
+	}
+	y, err := bytesParam(m, labelY, true)
+	if err != nil {
+		return nil, err
+	}
+
+	size := (ellipticCurves[crv].Params().BitSize + 7) / 8
+	if len(x) != size || len(y) != size {
+		return nil, errors.New("cose: invalid size of x or y")
+	}
+
+	point := append(append([]byte{4}, x...), y...)
+	if _, err := curve.NewPublicKey(point); err != nil {
+		return nil, errors.New("cose: invalid public key")
+	}
+
+	pub := &ecdsa.PublicKey{
+		Curve: ellipticCurves[crv],
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+
+	d, err := bytesParam(m, labelD, false)
+	if err != nil || d == nil {
+		return pub, err
+	}
+
+	priv, err := curve.NewPrivateKey(d)
+	if err != nil {
+		return nil, errors.New("cose: invalid private key")
+	}
+	if string(priv.PublicKey().Bytes()) != string(point) {
+		return nil, errors.New("cose: d does not match x and y")
+	}
+
+	return &ecdsa.PrivateKey{PublicKey: *pub, D: new(big.Int).SetBytes(d)}, nil
+}
+
+func decodeOKP(m cbor.Map) (jwk.CryptoKey, error) {
+	crv, _ := m[int64(labelCurve)].(int64)
+	if crv != CurveEd25519 {
+		return nil, errors.New("cose: unsupported curve")
+	}
+
+	x, err := bytesParam(m, labelX, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(x) != ed25519.PublicKeySize {
+		return nil, errors.New("cose: invalid size of x")
+	}
+
+	d, err := bytesParam(m, labelD, false)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return ed25519.PublicKey(x), nil
+	}
+
+	if len(d) != ed25519.SeedSize {
+		return nil, errors.New("cose: invalid size of d")
+	}
+	priv := ed25519.NewKeyFromSeed(d)
+	if !priv.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(x)) {
+		return nil, errors.New("cose: d does not match x")
+	}
+
+	return priv, nil
+}
+
+func decodeRSA(m cbor.Map) (jwk.CryptoKey, error) {
+	n, err := bytesParam(m, labelN, true)
+	if err != nil {
+		return nil, err
+	}
+	e, err := bytesParam(m, labelE, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(e) > 4 {
+		return nil, errors.New("cose: invalid e")
+	}
+
+	pub := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+
+	if _, ok := m[int64(labelRSAO)]; ok {
+		return nil, errors.New("cose: multi-prime RSA keys are not supported")
+	}
+
+	d, err := bytesParam(m, labelRSAD, false)
+	if err != nil || d == nil {
+		return pub, err
+	}
+
+	var primes [5][]byte
+	for i, label := range []int{labelP, labelQ, labelDP, labelDQ, labelQI} {
+		primes[i], err = bytesParam(m, label, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	priv := &rsa.PrivateKey{
+		PublicKey: *pub,
+		D:         new(big.Int).SetBytes(d),
+		Primes: []*big.Int{
+			new(big.Int).SetBytes(primes[0]),
+			new(big.Int).SetBytes(primes[1]),
+		},
+	}
+	if err := priv.Validate(); err != nil {
+		return nil, errors.New("cose: invalid private key")
+	}
+	priv.Precompute()
+
+	if priv.Precomputed.Dp.Cmp(new(big.Int).SetBytes(primes[2])) != 0 ||
+		priv.Precomputed.Dq.Cmp(new(big.Int).SetBytes(primes[3])) != 0 ||
+		priv.Precomputed.Qinv.Cmp(new(big.Int).SetBytes(primes[4])) != 0 {
+		return nil, errors.New("cose: inconsistent CRT parameters")
+	}
+
+	return priv, nil
+}
+
+func bytesParam(m cbor.Map, label int, required bool) ([]byte, error) {
+	v, ok := m[int64(label)]
+	if !ok {
+		if required {
+			return nil, errors.New("cose: missing required key parameter")
+		}
+		return nil, nil
+	}
+
+	b, ok := v.([]byte)
+	if !ok || len(b) == 0 {
+		return nil, errors.New("cose: invalid key parameter")
+	}
+
+	return b, nil
+}
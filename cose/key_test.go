@@ -0,0 +1,174 @@
+package cose
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ericyan/jwk"
+	"github.com/ericyan/jwk/internal/cbor"
+)
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Example key from RFC 9052, Appendix C.7.2.
+var rfc9052PrivateKey = cbor.Map{
+	int64(labelKeyType): int64(KeyTypeEC2),
+	int64(labelKeyID):   []byte("meriadoc.brandybuck@buckland.example"),
+	int64(labelCurve):   int64(CurveP256),
+	int64(labelX):       mustHex("65eda5a12577c2bae829437fe338701a10aaa375e1bb5b5de108de439c08551d"),
+	int64(labelY):       mustHex("1e52ed75701163f7f9e40ddf9f341b3dc9ba860af7e0ca7ca7e9eecd0084d19c"),
+	int64(labelD):       mustHex("aff907c99f9ad3aae6c4cdf21122bce2bd68b5283e6907154ad911840fa208cf"),
+}
+
+func TestParseRFC9052(t *testing.T) {
+	data, err := cbor.Marshal(rfc9052PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := Parse(data)
+	if err != nil {
+		t.Fatal("failed to parse COSE_Key:", err)
+	}
+
+	if _, ok := key.(*jwk.ECDSAPrivateKey); !ok {
+		t.Fatalf("unexpected key type %T", key)
+	}
+	if key.ID() != "meriadoc.brandybuck@buckland.example" {
+		t.Error("unexpected kid:", key.ID())
+	}
+
+	marshaled, err := MarshalPrivate(key)
+	if err != nil {
+		t.Fatal("failed to marshal COSE_Key:", err)
+	}
+	if string(marshaled) != string(data) {
+		t.Errorf("round-trip gave different encoding: %x", marshaled)
+	}
+
+	marshaled, err = Marshal(key)
+	if err != nil {
+		t.Fatal("failed to marshal COSE_Key:", err)
+	}
+	pub, err := Parse(marshaled)
+	if err != nil || pub.IsPrivate() || !jwk.Equal(pub, key.(*jwk.ECDSAPrivateKey).PublicKey()) {
+		t.Error("Marshal did not produce the public key")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	cases := []struct {
+		key    jwk.CryptoKey
+		params *jwk.Params
+	}{
+		{&ecKey.PublicKey, &jwk.Params{KeyID: "ec", Algorithm: "ES384", KeyOps: []string{"verify"}}},
+		{ecKey, &jwk.Params{KeyID: "ec", Algorithm: "ES384", KeyOps: []string{"sign"}}},
+		{&rsaKey.PublicKey, &jwk.Params{Algorithm: "RSA-OAEP-256", KeyOps: []string{"encrypt", "wrapKey"}}},
+		{rsaKey, &jwk.Params{Algorithm: "PS256"}},
+		{edKey.Public(), &jwk.Params{Algorithm: "EdDSA"}},
+		{edKey, &jwk.Params{KeyID: "ed", KeyOps: []string{"sign"}}},
+		{[]byte("0123456789abcdef"), &jwk.Params{Algorithm: "HS256", KeyOps: []string{"sign", "verify"}}},
+		{[]byte("0123456789abcdef"), &jwk.Params{Algorithm: "X-custom", KeyOps: []string{"x-custom"}}},
+	}
+
+	for _, c := range cases {
+		key, err := jwk.New(c.key, c.params)
+		if err != nil {
+			t.Fatal("failed to create key:", err)
+		}
+
+		data, err := MarshalPrivate(key)
+		if err != nil {
+			t.Errorf("failed to marshal %T: %s", key, err)
+			continue
+		}
+
+		parsed, err := Parse(data)
+		if err != nil {
+			t.Errorf("failed to parse %T: %s", key, err)
+			continue
+		}
+
+		if !jwk.EqualWithParams(key, parsed) {
+			t.Errorf("round-trip of %T gave different keys", key)
+		}
+	}
+}
+
+func TestMarshalSymmetricKeyOps(t *testing.T) {
+	key, _ := jwk.New([]byte("secret"), &jwk.Params{KeyOps: []string{"sign", "verify"}})
+
+	if _, err := Marshal(key); err == nil {
+		t.Error("expected error on marshaling symmetric key without MarshalPrivate")
+	}
+
+	data, err := MarshalPrivate(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, _ := cbor.Unmarshal(data)
+	ops := v.(cbor.Map)[int64(labelKeyOps)].([]interface{})
+	if ops[0] != int64(9) || ops[1] != int64(10) {
+		t.Error("unexpected key_ops:", ops)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	valid := func() cbor.Map {
+		m := cbor.Map{}
+		for k, v := range rfc9052PrivateKey {
+			m[k] = v
+		}
+		return m
+	}
+
+	cases := []func(m cbor.Map){
+		func(m cbor.Map) { delete(m, int64(labelKeyType)) },
+		func(m cbor.Map) { m[int64(labelKeyType)] = int64(99) },
+		func(m cbor.Map) { m[int64(labelKeyID)] = "text kid" },
+		func(m cbor.Map) { m[int64(labelAlg)] = int64(-65535) },
+		func(m cbor.Map) { m[int64(labelKeyOps)] = []interface{}{int64(42)} },
+		func(m cbor.Map) { m[int64(labelCurve)] = int64(CurveP384) },
+		func(m cbor.Map) { m[int64(labelY)] = true },
+		func(m cbor.Map) { delete(m, int64(labelX)) },
+		func(m cbor.Map) { m[int64(labelX)] = mustHex("00") },
+		func(m cbor.Map) {
+			m[int64(labelD)] = mustHex("aff907c99f9ad3aae6c4cdf21122bce2bd68b5283e6907154ad911840fa208ce")
+		},
+	}
+
+	for i, mutate := range cases {
+		m := valid()
+		mutate(m)
+
+		data, err := cbor.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Parse(data); err == nil {
+			t.Errorf("case %d: expected error on parsing invalid COSE_Key", i)
+		}
+	}
+
+	data, _ := cbor.Marshal(rfc9052PrivateKey)
+	if _, err := Parse(append(data, 0)); err == nil {
+		t.Error("expected error on trailing data")
+	}
+}
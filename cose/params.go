@@ -0,0 +1,78 @@
+package cose
+
+// Algorithms registered in the IANA "COSE Algorithms" registry that have
+// an equivalent in the IANA "JSON Web Signature and Encryption
+// Algorithms" registry, keyed by their COSE values.
+var algorithms = map[int64]string{
+	-7:   "ES256",
+	-35:  "ES384",
+	-36:  "ES512",
+	-8:   "EdDSA",
+	-257: "RS256",
+	-258: "RS384",
+	-259: "RS512",
+	-37:  "PS256",
+	-38:  "PS384",
+	-39:  "PS512",
+	5:    "HS256",
+	6:    "HS384",
+	7:    "HS512",
+	-3:   "A128KW",
+	-4:   "A192KW",
+	-5:   "A256KW",
+	1:    "A128GCM",
+	2:    "A192GCM",
+	3:    "A256GCM",
+	-6:   "dir",
+	-40:  "RSA-OAEP",
+	-41:  "RSA-OAEP-256",
+}
+
+// Algorithm returns the JOSE name of the COSE algorithm alg.
+func Algorithm(alg int64) (string, bool) {
+	name, ok := algorithms[alg]
+	return name, ok
+}
+
+func algorithmValue(name string) (int64, bool) {
+	for alg, n := range algorithms {
+		if n == name {
+			return alg, true
+		}
+	}
+
+	return 0, false
+}
+
+// Key operations defined in RFC 9052, Table 5, keyed by their COSE
+// values. MAC create and MAC verify map to sign and verify, which is
+// what JWK uses for symmetric keys.
+var keyOps = map[int64]string{
+	1:  "sign",
+	2:  "verify",
+	3:  "encrypt",
+	4:  "decrypt",
+	5:  "wrapKey",
+	6:  "unwrapKey",
+	7:  "deriveKey",
+	8:  "deriveBits",
+	9:  "sign",
+	10: "verify",
+}
+
+func keyOpValue(op string, symmetric bool) (int64, bool) {
+	switch {
+	case symmetric && op == "sign":
+		return 9, true
+	case symmetric && op == "verify":
+		return 10, true
+	}
+
+	for v, o := range keyOps {
+		if o == op && v < 9 {
+			return v, true
+		}
+	}
+
+	return 0, false
+}
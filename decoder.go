@@ -0,0 +1,246 @@
+package jwk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxKeySize is the default limit on the size of a single JWK
+// read by a Decoder.
+const DefaultMaxKeySize = 64 << 10
+
+// Decoder reads the keys of a JSON Web Key Set one at a time, so that
+// large sets can be processed without holding the whole document or all
+// of its keys in memory.
+type Decoder struct {
+	// MaxKeySize limits the size in bytes of each key, and of each other
+	// member of the set. If zero, DefaultMaxKeySize is used.
+	MaxKeySize int
+
+	// MaxKeys limits the number of keys in the set, including those
+	// skipped by Filter. If zero, there is no limit.
+	MaxKeys int
+
+	// Strict enables the checks of ParseStrict and ParseSetStrict.
+	Strict bool
+
+	// Filter, if not nil, is called with each key; keys for which it
+	// returns false are skipped.
+	Filter func(Key) bool
+
+	lr    *limitedReader
+	dec   *json.Decoder
+	state int
+	seen  map[string]bool // member names, in strict mode
+	keys  bool            // whether the keys member was read
+	index int
+	err   error
+}
+
+// Decoder states.
+const (
+	decoderStart = iota
+	decoderMembers
+	decoderKeys
+	decoderDone
+)
+
+// NewDecoder returns a new Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	lr := &limitedReader{r: r}
+	dec := json.NewDecoder(lr)
+	dec.UseNumber()
+
+	return &Decoder{lr: lr, dec: dec, seen: make(map[string]bool)}
+}
+
+// Next returns the next key in the set that passes Filter. It returns
+// io.EOF once the set has been read completely. Any other error is
+// permanent, and is returned by every subsequent call.
+func (d *Decoder) Next() (Key, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	for {
+		key, err := d.next()
+		if err != nil {
+			d.err = err
+			return nil, err
+		}
+		if key != nil && (d.Filter == nil || d.Filter(key)) {
+			return key, nil
+		}
+	}
+}
+
+// ForEach calls fn for each remaining key that passes Filter, stopping at
+// the first error.
+func (d *Decoder) ForEach(fn func(Key) error) error {
+	for {
+		key, err := d.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+}
+
+// next advances the decoder, returning the next key, or nil if it only
+// consumed some other part of the set.
+func (d *Decoder) next() (Key, error) {
+	d.resetLimit()
+
+	switch d.state {
+	case decoderStart:
+		if err := expectDelim(d.dec, '{'); err != nil {
+			return nil, d.readError(err, -1)
+		}
+		d.state = decoderMembers
+		return nil, nil
+	case decoderMembers:
+		if !d.dec.More() {
+			if err := expectDelim(d.dec, '}'); err != nil {
+				return nil, d.readError(err, -1)
+			}
+			if !d.keys {
+				return nil, parseError("keys", ErrMissingMember)
+			}
+			if err := checkEOF(d.dec); err != nil {
+				return nil, d.readError(err, -1)
+			}
+			d.state = decoderDone
+			return nil, io.EOF
+		}
+
+		tok, err := d.dec.Token()
+		if err != nil {
+			return nil, d.readError(err, -1)
+		}
+		name := tok.(string)
+		if d.Strict {
+			if err := checkName(d.seen, name, "keys"); err != nil {
+				return nil, err
+			}
+		}
+		if name == "keys" {
+			if d.keys {
+				return nil, parseError(name, ErrDuplicateMember)
+			}
+			d.keys = true
+
+			if err := expectDelim(d.dec, '['); err != nil {
+				return nil, d.readError(parseError("keys", fmt.Errorf("%w: not an array", ErrInvalidMember)), -1)
+			}
+			d.state = decoderKeys
+			return nil, nil
+		}
+
+		var raw json.RawMessage
+		if err := d.dec.Decode(&raw); err != nil {
+			return nil, d.readError(err, -1)
+		}
+		return nil, nil
+	case decoderKeys:
+		if !d.dec.More() {
+			if err := expectDelim(d.dec, ']'); err != nil {
+				return nil, d.readError(err, -1)
+			}
+			d.state = decoderMembers
+			return nil, nil
+		}
+
+		i := d.index
+		d.index++
+		if d.MaxKeys > 0 && i >= d.MaxKeys {
+			return nil, &ParseError{Index: i, Err: ErrTooManyKeys}
+		}
+
+		var raw json.RawMessage
+		if err := d.dec.Decode(&raw); err != nil {
+			return nil, d.readError(err, i)
+		}
+		if len(raw) > d.maxKeySize() {
+			return nil, &ParseError{Index: i, Err: ErrKeyTooLarge}
+		}
+
+		var key Key
+		var err error
+		if d.Strict {
+			key, err = ParseStrict(raw)
+		} else {
+			key, err = Parse(raw)
+		}
+		if err != nil {
+			return nil, inSet(err, i)
+		}
+		return key, nil
+	default:
+		return nil, io.EOF
+	}
+}
+
+func (d *Decoder) maxKeySize() int {
+	if d.MaxKeySize > 0 {
+		return d.MaxKeySize
+	}
+
+	return DefaultMaxKeySize
+}
+
+// resetLimit allows the next value to be read from the underlying
+// reader. The json.Decoder may have buffered part of it already, so the
+// memory used for a value is bounded by about twice the limit.
+func (d *Decoder) resetLimit() {
+	d.lr.n = int64(d.maxKeySize())
+}
+
+// readError converts an error from the json.Decoder into a *ParseError.
+func (d *Decoder) readError(err error, index int) error {
+	var parseErr *ParseError
+	switch {
+	case errors.Is(err, errLimitExceeded):
+		err = &ParseError{Index: index, Err: ErrKeyTooLarge}
+	case errors.As(err, &parseErr):
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		err = parseError("", fmt.Errorf("%w: %w", ErrMalformed, io.ErrUnexpectedEOF))
+	default:
+		err = parseError("", fmt.Errorf("%w: %w", ErrMalformed, err))
+	}
+
+	if index >= 0 {
+		return inSet(err, index)
+	}
+
+	return err
+}
+
+// errLimitExceeded is returned by limitedReader when the limit is hit.
+var errLimitExceeded = errors.New("jwk: read limit exceeded")
+
+// limitedReader reads from r until n bytes have been read, then fails.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if lr.n <= 0 {
+		return 0, errLimitExceeded
+	}
+	if int64(len(p)) > lr.n {
+		p = p[:lr.n]
+	}
+
+	n, err := lr.r.Read(p)
+	lr.n -= int64(n)
+
+	return n, err
+}
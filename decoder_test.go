@@ -0,0 +1,194 @@
+package jwk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func octKeySet(n int) string {
+	var b strings.Builder
+	b.WriteString(`{"issuer":"https://example.com","keys":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"kty":"oct","kid":"%d","k":"AAAA"}`, i)
+	}
+	b.WriteString(`],"meta":{"version":1}}`)
+
+	return b.String()
+}
+
+func TestDecoder(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(octKeySet(5000)))
+
+	n := 0
+	for {
+		key, err := dec.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal("failed to decode key:", err)
+		}
+		if key.ID() != fmt.Sprint(n) {
+			t.Fatalf("unexpected key %s at position %d", key.ID(), n)
+		}
+		n++
+	}
+	if n != 5000 {
+		t.Error("unexpected number of keys:", n)
+	}
+
+	if _, err := dec.Next(); err != io.EOF {
+		t.Error("expected io.EOF after end of set, got", err)
+	}
+}
+
+func TestDecoderFilter(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(octKeySet(100)))
+	dec.Filter = func(key Key) bool {
+		return strings.HasSuffix(key.ID(), "7")
+	}
+
+	var ids []string
+	err := dec.ForEach(func(key Key) error {
+		ids = append(ids, key.ID())
+		return nil
+	})
+	if err != nil {
+		t.Fatal("failed to decode set:", err)
+	}
+	if len(ids) != 10 || ids[0] != "7" || ids[9] != "97" {
+		t.Error("unexpected keys:", ids)
+	}
+
+	stop := errors.New("stop")
+	dec = NewDecoder(strings.NewReader(octKeySet(100)))
+	if err := dec.ForEach(func(Key) error { return stop }); err != stop {
+		t.Error("ForEach did not stop on callback error:", err)
+	}
+}
+
+func TestDecoderMaxKeys(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(octKeySet(10)))
+	dec.MaxKeys = 5
+	dec.Filter = func(Key) bool { return false }
+
+	_, err := dec.Next()
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Index != 5 || !errors.Is(err, ErrTooManyKeys) {
+		t.Error("unexpected error:", err)
+	}
+	if _, again := dec.Next(); again != err {
+		t.Error("error is not permanent")
+	}
+}
+
+// endlessReader yields the prefix followed by an endless string.
+type endlessReader struct {
+	prefix string
+	read   int
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	n := copy(p, r.prefix)
+	r.prefix = r.prefix[n:]
+	for i := n; i < len(p); i++ {
+		p[i] = 'A'
+	}
+	r.read += len(p)
+
+	return len(p), nil
+}
+
+func TestDecoderMaxKeySize(t *testing.T) {
+	r := &endlessReader{prefix: `{"keys":[{"kty":"oct","k":"AAAA"},{"kty":"oct","k":"`}
+	dec := NewDecoder(r)
+	dec.MaxKeySize = 1024
+
+	if _, err := dec.Next(); err != nil {
+		t.Fatal("failed to decode first key:", err)
+	}
+
+	_, err := dec.Next()
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Index != 1 || !errors.Is(err, ErrKeyTooLarge) {
+		t.Error("unexpected error:", err)
+	}
+	if r.read > 4*dec.MaxKeySize {
+		t.Errorf("read %d bytes for a limit of %d", r.read, dec.MaxKeySize)
+	}
+
+	// Other members are limited too.
+	dec = NewDecoder(&endlessReader{prefix: `{"meta":"`})
+	dec.MaxKeySize = 1024
+	if _, err := dec.Next(); !errors.Is(err, ErrKeyTooLarge) {
+		t.Error("unexpected error:", err)
+	}
+}
+
+func TestDecoderStrict(t *testing.T) {
+	data := `{"keys":[{"kty":"oct","k":"AAAA"},{"kty":"oct","k":"AAAA","k":"AQAB"}]}`
+
+	dec := NewDecoder(strings.NewReader(data))
+	if err := dec.ForEach(func(Key) error { return nil }); err != nil {
+		t.Error("lenient decoder rejected duplicate member:", err)
+	}
+
+	dec = NewDecoder(strings.NewReader(data))
+	dec.Strict = true
+	err := dec.ForEach(func(Key) error { return nil })
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Index != 1 || !errors.Is(err, ErrDuplicateMember) {
+		t.Error("unexpected error:", err)
+	}
+	for _, c := range []struct {
+		jwks string
+		err  error
+	}{
+		{`{"keys":[],"Keys":[]}`, ErrDuplicateMember},
+		{`{"Keys":[{"kty":"oct","k":"AAAA"}]}`, ErrInvalidMember},
+		{`{"meta":1,"META":2,"keys":[]}`, ErrDuplicateMember},
+		{`{"keys":[{"kty":"oct","k":"AQAB","K":"AAAA"}]}`, ErrDuplicateMember},
+	} {
+		dec := NewDecoder(strings.NewReader(c.jwks))
+		dec.Strict = true
+		if err := dec.ForEach(func(Key) error { return nil }); !errors.Is(err, c.err) {
+			t.Errorf("%s: unexpected error %v", c.jwks, err)
+		}
+	}
+}
+
+func TestDecoderInvalid(t *testing.T) {
+	cases := []struct {
+		jwks  string
+		index int
+		err   error
+	}{
+		{`{"kty":"oct","k":"AAAA"}`, -1, ErrMissingMember},
+		{`{"keys":{}}`, -1, ErrInvalidMember},
+		{`{"keys":[],"keys":[]}`, -1, ErrDuplicateMember},
+		{`{"keys":[{"kty":"oct","k":"AAAA"},{"kty":"oct"}]}`, 1, ErrMissingMember},
+		{`{"keys":[{"kty":"oct","k":"AAAA"},{"kty":"oct",`, 1, ErrMalformed},
+		{`{"keys":[]} {}`, -1, ErrTrailingData},
+		{`[]`, -1, ErrMalformed},
+		{``, -1, ErrMalformed},
+	}
+
+	for _, c := range cases {
+		dec := NewDecoder(strings.NewReader(c.jwks))
+		err := dec.ForEach(func(Key) error { return nil })
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Index != c.index || !errors.Is(err, c.err) {
+			t.Errorf("%s: unexpected error %v", c.jwks, err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package jwk
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrDestroyed is returned when using a key after it has been destroyed.
+var ErrDestroyed = errors.New("jwk: key destroyed")
+
+// Destroyer is implemented by keys holding private key material that can
+// be wiped from memory.
+type Destroyer interface {
+	Destroy()
+}
+
+// zeroBigInt overwrites the words of x with zeros and sets it to zero.
+func zeroBigInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+
+	words := x.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
+
+// Destroy overwrites the private key material, both in the JWK members
+// and in the underlying ecdsa.PrivateKey, with zeros, and releases the
+// ecdsa.PrivateKey. Afterwards, Sign and MarshalPrivate return
+// ErrDestroyed and CryptoKey returns nil, while the public key remains
+// usable.
+//
+// The ecdsa.PrivateKey passed to NewECDSAPrivateKey is the one zeroed, so
+// the caller's copy of D is wiped too. Copies held elsewhere, such as
+// keys returned by MarshalPrivate, are not affected.
+func (key *ECDSAPrivateKey) Destroy() {
+	key.D.Zero()
+	if key.priv != nil {
+		zeroBigInt(key.priv.D)
+	}
+	key.priv = nil
+}
+
+// Destroy overwrites the private key material, both in the JWK members
+// and in the underlying rsa.PrivateKey, with zeros, and releases the
+// rsa.PrivateKey. Afterwards, Sign, Decrypt and MarshalPrivate return
+// ErrDestroyed and CryptoKey returns nil, while the public key remains
+// usable.
+//
+// The rsa.PrivateKey passed to NewRSAPrivateKey is the one zeroed, so the
+// caller's copy of D, the primes and the CRT values is wiped too.
+// However, the crypto/rsa package keeps its own precomputed copy of the
+// key material, which cannot be wiped: it is only left for the garbage
+// collector, and a caller still holding the rsa.PrivateKey can sign with
+// it. Keys returned by MarshalPrivate are not affected either.
+func (key *RSAPrivateKey) Destroy() {
+	for _, v := range []interface{ Zero() }{key.D, key.P, key.Q, key.DP, key.DQ, key.QI} {
+		v.Zero()
+	}
+
+	if priv := key.priv; priv != nil {
+		zeroBigInt(priv.D)
+		for _, p := range priv.Primes {
+			zeroBigInt(p)
+		}
+		zeroBigInt(priv.Precomputed.Dp)
+		zeroBigInt(priv.Precomputed.Dq)
+		zeroBigInt(priv.Precomputed.Qinv)
+		for _, crt := range priv.Precomputed.CRTValues {
+			zeroBigInt(crt.Exp)
+			zeroBigInt(crt.Coeff)
+			zeroBigInt(crt.R)
+		}
+	}
+	key.priv = nil
+}
+
+// Destroy overwrites the private key material, both in the JWK members
+// and in the underlying ed25519.PrivateKey, with zeros, and releases the
+// ed25519.PrivateKey. Afterwards, Sign and MarshalPrivate return
+// ErrDestroyed and CryptoKey returns nil, while the public key remains
+// usable.
+//
+// The ed25519.PrivateKey passed to NewEd25519PrivateKey is the one
+// zeroed, so the caller's copy is wiped too.
+func (key *Ed25519PrivateKey) Destroy() {
+	key.D.Zero()
+	for i := range key.priv {
+		key.priv[i] = 0
+	}
+	key.priv = nil
+}
+
+// Destroy overwrites the key with zeros and releases it. Afterwards,
+// MarshalPrivate returns ErrDestroyed and CryptoKey returns nil, so the
+// key can no longer be used to compute or verify MACs.
+//
+// As the key shares its octets with the slice passed to
+// NewOctetSequenceKey and returned by CryptoKey, those are zeroed too.
+func (key *OctetSequenceKey) Destroy() {
+	key.K.Zero()
+	key.K = nil
+}
+
+// Destroy destroys all keys in the set that implement Destroyer, and
+// removes all keys from the set.
+func (s *Set) Destroy() {
+	for _, key := range s.Keys {
+		if d, ok := key.(Destroyer); ok {
+			d.Destroy()
+		}
+	}
+	s.Keys = nil
+}
@@ -0,0 +1,157 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+func isZero(b []byte) bool {
+	for _, x := range b {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// checkDestroyed checks that the destroyed key can no longer be used,
+// while its public key remains available.
+func checkDestroyed(t *testing.T, key Key, pub crypto.PublicKey) {
+	t.Helper()
+
+	if key.CryptoKey() != nil {
+		t.Error("destroyed key still exposes its crypto key")
+	}
+	if _, err := MarshalPrivate(key); err != ErrDestroyed {
+		t.Error("expected ErrDestroyed on marshaling destroyed key, got", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return
+	}
+	digest := sha256.Sum256([]byte("foo"))
+	opts := crypto.SignerOpts(crypto.SHA256)
+	if _, isEd25519 := pub.(ed25519.PublicKey); isEd25519 {
+		opts = crypto.Hash(0)
+	}
+	if _, err := signer.Sign(rand.Reader, digest[:], opts); err != ErrDestroyed {
+		t.Error("expected ErrDestroyed on signing with destroyed key, got", err)
+	}
+	if p, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !p.Equal(pub) {
+		t.Error("destroyed key lost its public key")
+	}
+	if pk := key.(interface{ PublicKey() Key }).PublicKey(); pk == nil || pk.CryptoKey() == nil {
+		t.Error("destroyed key lost its public key")
+	}
+}
+
+func TestECDSAPrivateKeyDestroy(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	key, _ := NewECDSAPrivateKey(priv, nil)
+	d := key.D.Bytes()
+	words := priv.D.Bits()
+
+	key.Destroy()
+
+	if !isZero(d) || priv.D.Sign() != 0 {
+		t.Error("private key material not zeroed")
+	}
+	for _, w := range words {
+		if w != 0 {
+			t.Error("big.Int words not zeroed")
+		}
+	}
+
+	checkDestroyed(t, key, &priv.PublicKey)
+}
+
+func TestRSAPrivateKeyDestroy(t *testing.T) {
+	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	key, _ := NewRSAPrivateKey(priv, nil)
+	members := [][]byte{key.D.Bytes(), key.P.Bytes(), key.Q.Bytes(), key.DP.Bytes(), key.DQ.Bytes(), key.QI.Bytes()}
+	words := [][]big.Word{priv.D.Bits(), priv.Primes[0].Bits(), priv.Primes[1].Bits(), priv.Precomputed.Dp.Bits()}
+
+	key.Destroy()
+
+	for _, m := range members {
+		if !isZero(m) {
+			t.Error("private member not zeroed")
+		}
+	}
+	for _, ws := range words {
+		for _, w := range ws {
+			if w != 0 {
+				t.Error("big.Int words not zeroed")
+			}
+		}
+	}
+	if priv.D.Sign() != 0 || priv.Primes[0].Sign() != 0 || priv.Precomputed.Qinv.Sign() != 0 {
+		t.Error("rsa.PrivateKey not zeroed")
+	}
+	if priv.N.Sign() == 0 {
+		t.Error("public key material zeroed")
+	}
+
+	checkDestroyed(t, key, &priv.PublicKey)
+	if _, err := key.Decrypt(rand.Reader, []byte("foo"), nil); err != ErrDestroyed {
+		t.Error("expected ErrDestroyed on decrypting with destroyed key, got", err)
+	}
+}
+
+func TestEd25519PrivateKeyDestroy(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	key, _ := NewEd25519PrivateKey(priv, nil)
+	d := key.D.Bytes()
+
+	key.Destroy()
+
+	if !isZero(d) || !isZero(priv) {
+		t.Error("private key material not zeroed")
+	}
+
+	checkDestroyed(t, key, pub)
+}
+
+func TestOctetSequenceKeyDestroy(t *testing.T) {
+	secret := []byte("secret")
+	key, _ := NewOctetSequenceKey(secret, nil)
+	other, _ := NewOctetSequenceKey(make([]byte, len(secret)), nil)
+
+	key.Destroy()
+
+	if !isZero(secret) {
+		t.Error("key not zeroed")
+	}
+	if Equal(key, other) {
+		t.Error("destroyed key equal to all-zero key")
+	}
+
+	checkDestroyed(t, key, nil)
+}
+
+func TestSetDestroy(t *testing.T) {
+	secret := []byte("secret")
+	oct, _ := NewOctetSequenceKey(secret, nil)
+	pub, _ := NewECDSAPublicKey(&ecdsaTestKeyP256.PublicKey, nil)
+	set := &Set{Keys: []Key{oct, pub}}
+
+	set.Destroy()
+
+	if !isZero(secret) {
+		t.Error("oct key not zeroed")
+	}
+	if len(set.Keys) != 0 {
+		t.Error("keys not removed from set")
+	}
+	if ecdsaTestKeyP256.X.Sign() == 0 {
+		t.Error("public key zeroed")
+	}
+}
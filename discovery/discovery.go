@@ -0,0 +1,149 @@
+// Package discovery resolves JSON Web Key Sets from OpenID Connect
+// Discovery and OAuth 2.0 Authorization Server Metadata (RFC 8414).
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ericyan/jwk"
+)
+
+// Well-known URI suffixes for provider metadata.
+const (
+	OpenIDConfiguration      = "/.well-known/openid-configuration"
+	OAuthAuthorizationServer = "/.well-known/oauth-authorization-server"
+)
+
+// maxResponseSize limits the size of documents fetched from the network.
+const maxResponseSize = 1 << 20
+
+// errNotFound indicates that a metadata document does not exist.
+var errNotFound = errors.New("discovery: metadata not found")
+
+// Metadata contains the provider metadata members used to locate keys.
+type Metadata struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// Resolver resolves the JSON Web Key Set of an issuer.
+type Resolver struct {
+	// Client is used to make HTTP requests. If nil, http.DefaultClient
+	// is used.
+	Client *http.Client
+}
+
+// Resolve fetches the JSON Web Key Set of issuer using a zero Resolver.
+func Resolve(ctx context.Context, issuer string) (*jwk.Set, error) {
+	return new(Resolver).Resolve(ctx, issuer)
+}
+
+// Metadata fetches and validates the metadata of issuer. The OpenID
+// Connect configuration is tried first, followed by the OAuth 2.0
+// authorization server metadata.
+func (r *Resolver) Metadata(ctx context.Context, issuer string) (*Metadata, error) {
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: invalid issuer: %v", err)
+	}
+	if u.Scheme != "https" || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return nil, errors.New("discovery: invalid issuer, must be an https URL without query or fragment")
+	}
+	path := strings.TrimSuffix(u.EscapedPath(), "/")
+
+	candidates := []string{
+		// OpenID Connect Discovery 1.0, Section 4
+		u.Scheme + "://" + u.Host + path + OpenIDConfiguration,
+		// RFC 8414, Section 3
+		u.Scheme + "://" + u.Host + OAuthAuthorizationServer + path,
+	}
+
+	for _, location := range candidates {
+		md := new(Metadata)
+		err := r.get(ctx, location, md)
+		if err == errNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if md.Issuer != issuer {
+			return nil, fmt.Errorf("discovery: issuer mismatch, got '%s'", md.Issuer)
+		}
+		if md.JWKSURI == "" {
+			return nil, errors.New("discovery: invalid metadata, missing jwks_uri")
+		}
+
+		return md, nil
+	}
+
+	return nil, errNotFound
+}
+
+// Resolve fetches the JSON Web Key Set of issuer.
+func (r *Resolver) Resolve(ctx context.Context, issuer string) (*jwk.Set, error) {
+	md, err := r.Metadata(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(md.JWKSURI)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, errors.New("discovery: invalid metadata, jwks_uri must be an https URL")
+	}
+
+	set := new(jwk.Set)
+	err = r.get(ctx, md.JWKSURI, set)
+	if err == errNotFound {
+		return nil, errors.New("discovery: jwks_uri not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// get fetches the JSON document at location and decodes it into v.
+func (r *Resolver) get(ctx context.Context, location string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("discovery: unexpected status %d from %s", resp.StatusCode, location)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > maxResponseSize {
+		return fmt.Errorf("discovery: response from %s too large", location)
+	}
+
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,84 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testJWKS = `{"keys":[{"kty":"oct","k":"GawgguFyGrWKav7AX4VKUg","kid":"foo"}]}`
+
+func newTestServer(t *testing.T, wellKnown func(issuer string) string, issuer func(base string) string) (*httptest.Server, string) {
+	mux := http.NewServeMux()
+	srv := httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+
+	iss := srv.URL + "/tenant"
+	mux.HandleFunc(wellKnown("/tenant"), func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Metadata{
+			Issuer:  issuer(iss),
+			JWKSURI: srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testJWKS))
+	})
+
+	return srv, iss
+}
+
+func TestResolve(t *testing.T) {
+	cases := []struct {
+		name      string
+		wellKnown func(path string) string
+	}{
+		{"openid-configuration", func(path string) string { return path + OpenIDConfiguration }},
+		{"oauth-authorization-server", func(path string) string { return OAuthAuthorizationServer + path }},
+	}
+
+	for _, c := range cases {
+		srv, issuer := newTestServer(t, c.wellKnown, func(iss string) string { return iss })
+		r := &Resolver{Client: srv.Client()}
+
+		set, err := r.Resolve(context.Background(), issuer)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if len(set.Keys) != 1 || set.Keys[0].ID() != "foo" {
+			t.Errorf("%s: unexpected key set", c.name)
+		}
+	}
+}
+
+func TestResolveIssuerMismatch(t *testing.T) {
+	wellKnown := func(path string) string { return path + OpenIDConfiguration }
+	srv, issuer := newTestServer(t, wellKnown, func(iss string) string { return iss + "/evil" })
+	r := &Resolver{Client: srv.Client()}
+
+	_, err := r.Resolve(context.Background(), issuer)
+	if err == nil {
+		t.Error("expected error on issuer mismatch")
+	}
+}
+
+func TestResolveInvalidIssuer(t *testing.T) {
+	for _, issuer := range []string{"", "http://example.com", "https://example.com/?q=1", "https://"} {
+		_, err := Resolve(context.Background(), issuer)
+		if err == nil {
+			t.Errorf("expected error on invalid issuer '%s'", issuer)
+		}
+	}
+}
+
+func TestResolveNotFound(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	r := &Resolver{Client: srv.Client()}
+
+	_, err := r.Resolve(context.Background(), srv.URL)
+	if err == nil {
+		t.Error("expected error on missing metadata")
+	}
+}
@@ -1,11 +1,11 @@
 package jwk
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
-	"encoding/json"
-	"errors"
-	"fmt"
+	"io"
+	"math/big"
 
 	"github.com/ericyan/jwk/internal/base64url"
 )
@@ -32,11 +32,11 @@ func NewECDSAPublicKey(pub *ecdsa.PublicKey, params *Params) (*ECDSAPublicKey, e
 		params.KeyType = TypeEC
 	}
 	if params.KeyType != TypeEC {
-		return nil, errors.New("jwk: invalid params, wrong key type")
+		return nil, ErrWrongKeyType
 	}
 
 	if pub == nil || pub.X == nil || pub.Y == nil {
-		return nil, errors.New("jwk: invalid crypto key")
+		return nil, ErrInvalidCryptoKey
 	}
 
 	var crv string
@@ -48,7 +48,7 @@ func NewECDSAPublicKey(pub *ecdsa.PublicKey, params *Params) (*ECDSAPublicKey, e
 	case elliptic.P521():
 		crv = "P-521"
 	default:
-		return nil, fmt.Errorf("jwk: unsupported elliptic curve")
+		return nil, ErrUnsupportedCurve
 	}
 
 	return &ECDSAPublicKey{
@@ -63,19 +63,19 @@ func NewECDSAPublicKey(pub *ecdsa.PublicKey, params *Params) (*ECDSAPublicKey, e
 // ParseECDSAPublicKey parses the JSON Web Key as an ECDSA public key.
 func ParseECDSAPublicKey(jwk []byte) (*ECDSAPublicKey, error) {
 	key := new(ECDSAPublicKey)
-	err := json.Unmarshal(jwk, key)
+	err := unmarshalKey(jwk, key, "x", "y")
 	if err != nil {
 		return nil, err
 	}
 
 	if key.KeyType != TypeEC {
-		return nil, errors.New("jwk: invalid JWT, wrong type")
+		return nil, parseError("kty", ErrWrongKeyType)
 	}
 	if key.X == nil {
-		return nil, errors.New("jwk: invalid JWT, missing N")
+		return nil, parseError("x", ErrMissingMember)
 	}
 	if key.Y == nil {
-		return nil, errors.New("jwk: invalid JWT, missing E")
+		return nil, parseError("y", ErrMissingMember)
 	}
 
 	var curve elliptic.Curve
@@ -87,7 +87,7 @@ func ParseECDSAPublicKey(jwk []byte) (*ECDSAPublicKey, error) {
 	case "P-521":
 		curve = elliptic.P521()
 	default:
-		return nil, fmt.Errorf("jwk: unsupported elliptic curve")
+		return nil, parseError("crv", ErrUnsupportedCurve)
 	}
 
 	key.pub = &ecdsa.PublicKey{
@@ -99,6 +99,11 @@ func ParseECDSAPublicKey(jwk []byte) (*ECDSAPublicKey, error) {
 	return key, nil
 }
 
+// IsPrivate reports whether the key contains private key material.
+func (key *ECDSAPublicKey) IsPrivate() bool {
+	return false
+}
+
 // CryptoKey returns the underlying cryptographic key.
 func (key *ECDSAPublicKey) CryptoKey() CryptoKey {
 	return key.pub
@@ -107,7 +112,7 @@ func (key *ECDSAPublicKey) CryptoKey() CryptoKey {
 // ECDSAPrivateKey represents an ECDSA private key, which contains
 // algorithm-specific parameters defined in RFC 7518, Section 6.2.2.
 //
-// ECDSAPrivate implements the Key interface.
+// ECDSAPrivateKey implements the Key and crypto.Signer interfaces.
 type ECDSAPrivateKey struct {
 	*ECDSAPublicKey
 	D *base64url.Value `json:"d"`
@@ -118,7 +123,7 @@ type ECDSAPrivateKey struct {
 // NewECDSAPrivateKey creates a new ECDSAPrivate.
 func NewECDSAPrivateKey(priv *ecdsa.PrivateKey, params *Params) (*ECDSAPrivateKey, error) {
 	if priv == nil {
-		return nil, errors.New("jwk: invalid crypto key")
+		return nil, ErrInvalidCryptoKey
 	}
 
 	pub, err := NewECDSAPublicKey(&priv.PublicKey, params)
@@ -138,13 +143,13 @@ func NewECDSAPrivateKey(priv *ecdsa.PrivateKey, params *Params) (*ECDSAPrivateKe
 // ParseECDSAPrivateKey parses the JSON Web Key as an ECDSA private key.
 func ParseECDSAPrivateKey(jwk []byte) (*ECDSAPrivateKey, error) {
 	key := new(ECDSAPrivateKey)
-	err := json.Unmarshal(jwk, key)
+	err := unmarshalKey(jwk, key, "x", "y", "d")
 	if err != nil {
 		return nil, err
 	}
 
 	if key.D == nil {
-		return nil, errors.New("jwk: invalid JWT, missing D")
+		return nil, parseError("d", ErrMissingMember)
 	}
 
 	pub, err := ParseECDSAPublicKey(jwk)
@@ -162,7 +167,49 @@ func ParseECDSAPrivateKey(jwk []byte) (*ECDSAPrivateKey, error) {
 	return key, nil
 }
 
-// CryptoKey returns the underlying cryptographic key.
+// IsPrivate reports whether the key contains private key material.
+func (key *ECDSAPrivateKey) IsPrivate() bool {
+	return true
+}
+
+// CryptoKey returns the underlying cryptographic key, or nil if the key
+// has been destroyed.
 func (key *ECDSAPrivateKey) CryptoKey() CryptoKey {
+	if key.priv == nil {
+		return nil
+	}
+
 	return key.priv
 }
+
+// PublicKey returns the public counterpart of the private key. The
+// returned key does not share any state with the private key.
+func (key *ECDSAPrivateKey) PublicKey() Key {
+	pub := &ecdsa.PublicKey{
+		Curve: key.pub.Curve,
+		X:     new(big.Int).Set(key.pub.X),
+		Y:     new(big.Int).Set(key.pub.Y),
+	}
+
+	return &ECDSAPublicKey{
+		key.Params.public(),
+		key.CRV,
+		base64url.NewBigInt(pub.X),
+		base64url.NewBigInt(pub.Y),
+		pub,
+	}
+}
+
+// Public returns the underlying ECDSA public key.
+func (key *ECDSAPrivateKey) Public() crypto.PublicKey {
+	return key.pub
+}
+
+// Sign signs digest with the private key, as in ecdsa.PrivateKey.
+func (key *ECDSAPrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if key.priv == nil {
+		return nil, ErrDestroyed
+	}
+
+	return key.priv.Sign(rand, digest, opts)
+}
@@ -4,7 +4,9 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/x509"
 	"encoding/json"
+	"math/big"
 	"testing"
 )
 
@@ -48,10 +50,8 @@ func TestECDSAPublicKey(t *testing.T) {
 		t.Error("failed to unmarshal valid ECDSA public key:", err)
 	}
 
-	orgPub := key.CryptoKey().(*ecdsa.PublicKey)
-	parsedPub := parsed.CryptoKey().(*ecdsa.PublicKey)
-	if orgPub.Curve == parsedPub.Curve && orgPub.X.Cmp(parsedPub.X) != 0 && orgPub.Y.Cmp(parsedPub.Y) != 0 {
-		t.Error("round-trip of RSA public key gave different raw keys")
+	if !EqualWithParams(key, parsed) {
+		t.Error("round-trip of ECDSA public key gave different keys")
 	}
 }
 
@@ -82,7 +82,7 @@ func TestECDSAPrivateKey(t *testing.T) {
 		t.Error("excepted error on parsing invalid ECDSA public key key (missing n, d, q)")
 	}
 
-	marshaled, err := json.Marshal(key)
+	marshaled, err := MarshalPrivate(key)
 	if err != nil {
 		t.Error("failed to marshal valid ECDSA private key:", err)
 	}
@@ -92,11 +92,57 @@ func TestECDSAPrivateKey(t *testing.T) {
 		t.Error("failed to unmarshal valid ECDSA private key:", err)
 	}
 
-	orgPriv := key.CryptoKey().(*ecdsa.PrivateKey)
-	orgPub := orgPriv.PublicKey
-	parsedPriv := parsed.CryptoKey().(*ecdsa.PrivateKey)
-	parsedPub := parsedPriv.PublicKey
-	if orgPub.Curve == parsedPub.Curve && orgPub.X.Cmp(parsedPub.X) != 0 && orgPub.Y.Cmp(parsedPub.Y) != 0 && orgPriv.D.Cmp(parsedPriv.D) != 0 {
-		t.Error("round-trip of ECDSA private key gave different raw keys")
+	if !EqualWithParams(key, parsed) {
+		t.Error("round-trip of ECDSA private key gave different keys")
+	}
+}
+
+func TestECDSAPrivateKeyPublic(t *testing.T) {
+	key, err := NewECDSAPrivateKey(ecdsaTestKeyP256, &Params{KeyID: "foo", KeyOps: []string{"sign"}})
+	if err != nil {
+		t.Fatal("failed to create valid ECDSA private key:", err)
+	}
+
+	pub, ok := key.PublicKey().(*ECDSAPublicKey)
+	if !ok {
+		t.Fatal("public key is not an ECDSA public key")
+	}
+	if pub.ID() != "foo" || len(pub.KeyOps) != 1 || pub.KeyOps[0] != "verify" {
+		t.Error("unexpected public key params:", pub.Params)
+	}
+	if pub.Params == key.Params {
+		t.Error("public key shares params with private key")
+	}
+	if key.KeyOps[0] != "sign" {
+		t.Error("private key params modified")
+	}
+
+	pub.CryptoKey().(*ecdsa.PublicKey).X.SetInt64(0)
+	if ecdsaTestKeyP256.X.Sign() == 0 {
+		t.Error("public key shares crypto key with private key")
+	}
+}
+
+func TestECDSAPrivateKeySigner(t *testing.T) {
+	key, err := NewECDSAPrivateKey(ecdsaTestKeyP256, nil)
+	if err != nil {
+		t.Fatal("failed to create valid ECDSA private key:", err)
+	}
+
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1)}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatal("failed to create certificate with ECDSA private key:", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if !ecdsaTestKeyP256.PublicKey.Equal(cert.PublicKey) {
+		t.Error("certificate public key mismatch")
+	}
+	if cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) != nil {
+		t.Error("invalid certificate signature")
 	}
 }
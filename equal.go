@@ -0,0 +1,125 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/subtle"
+)
+
+// Equal reports whether a and b are keys of the same type holding the
+// same key material. Params are not compared. Symmetric keys are compared
+// in constant time, and keys backed by an opaque crypto.Signer are
+// compared by their public keys.
+func Equal(a, b Key) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	if isOpaque(a.CryptoKey()) || isOpaque(b.CryptoKey()) {
+		// Opaque private keys are compared by their public keys, as the
+		// private key material is not accessible. Both sides must be
+		// private keys for the comparison to be symmetric.
+		x, ok := a.CryptoKey().(crypto.Signer)
+		if !ok {
+			return false
+		}
+		y, ok := b.CryptoKey().(crypto.Signer)
+		if !ok {
+			return false
+		}
+		pub, ok := x.Public().(interface{ Equal(crypto.PublicKey) bool })
+		return ok && pub.Equal(y.Public())
+	}
+
+	switch x := a.CryptoKey().(type) {
+	case *ecdsa.PublicKey:
+		y, ok := b.CryptoKey().(*ecdsa.PublicKey)
+		return ok && x.Equal(y)
+	case *ecdsa.PrivateKey:
+		y, ok := b.CryptoKey().(*ecdsa.PrivateKey)
+		return ok && x.Equal(y)
+	case *rsa.PublicKey:
+		y, ok := b.CryptoKey().(*rsa.PublicKey)
+		return ok && x.Equal(y)
+	case *rsa.PrivateKey:
+		y, ok := b.CryptoKey().(*rsa.PrivateKey)
+		return ok && x.Equal(y)
+	case ed25519.PublicKey:
+		y, ok := b.CryptoKey().(ed25519.PublicKey)
+		return ok && x.Equal(y)
+	case ed25519.PrivateKey:
+		y, ok := b.CryptoKey().(ed25519.PrivateKey)
+		return ok && x.Equal(y)
+	case []byte:
+		y, ok := b.CryptoKey().([]byte)
+		return ok && subtle.ConstantTimeCompare(x, y) == 1
+	default:
+		return false
+	}
+}
+
+// isOpaque reports whether key is a crypto.Signer whose private key
+// material is not accessible.
+func isOpaque(key CryptoKey) bool {
+	switch key.(type) {
+	case *ecdsa.PrivateKey, *rsa.PrivateKey, ed25519.PrivateKey:
+		return false
+	}
+
+	_, ok := key.(crypto.Signer)
+	return ok
+}
+
+// EqualWithParams is like Equal, but also requires the keys to have the
+// same params.
+func EqualWithParams(a, b Key) bool {
+	if !Equal(a, b) {
+		return false
+	}
+	if a == nil {
+		return true
+	}
+
+	return equalParams(a.Parameters(), b.Parameters())
+}
+
+// equalParams reports whether a and b are equal. The key operations are
+// compared as sets, as their order is not significant.
+func equalParams(a, b *Params) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if a.KeyType != b.KeyType || a.KeyUse != b.KeyUse || a.Algorithm != b.Algorithm || a.KeyID != b.KeyID {
+		return false
+	}
+	if a.X509SHA256Thumbprint != b.X509SHA256Thumbprint {
+		return false
+	}
+
+	for _, op := range a.KeyOps {
+		if !containsString(b.KeyOps, op) {
+			return false
+		}
+	}
+	for _, op := range b.KeyOps {
+		if !containsString(a.KeyOps, op) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsString reports whether s is in list.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
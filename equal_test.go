@@ -0,0 +1,57 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	otherECDSAKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	ecPriv, _ := NewECDSAPrivateKey(ecdsaTestKeyP256, &Params{KeyID: "foo"})
+	ecPub, _ := NewECDSAPublicKey(&ecdsaTestKeyP256.PublicKey, &Params{KeyID: "bar"})
+	ecOther, _ := NewECDSAPublicKey(&otherECDSAKey.PublicKey, &Params{KeyID: "bar"})
+	rsaPriv, _ := NewRSAPrivateKey(rsaTestKey, nil)
+	rsaPub, _ := NewRSAPublicKey(&rsaTestKey.PublicKey, nil)
+	oct1, _ := NewOctetSequenceKey([]byte{1, 2, 3, 4}, &Params{KeyOps: []string{"sign", "verify"}})
+	oct2, _ := NewOctetSequenceKey([]byte{1, 2, 3, 4}, &Params{KeyOps: []string{"verify", "sign"}})
+	oct3, _ := NewOctetSequenceKey([]byte{1, 2, 3, 5}, nil)
+	signer, _ := NewSignerKey(&opaqueSigner{ecdsaTestKeyP256}, &Params{KeyID: "foo"})
+	signerOther, _ := NewSignerKey(&opaqueSigner{otherECDSAKey}, &Params{KeyID: "foo"})
+
+	cases := []struct {
+		a, b       Key
+		equal      bool
+		withParams bool
+	}{
+		{ecPriv, ecPriv, true, true},
+		{ecPriv.PublicKey(), ecPub, true, false},
+		{ecPub, ecOther, false, false},
+		{ecPriv, ecPub, false, false},
+		{rsaPriv.PublicKey(), rsaPub, true, true},
+		{rsaPriv, rsaPub, false, false},
+		{ecPub, rsaPub, false, false},
+		{oct1, oct2, true, true},
+		{oct1, oct3, false, false},
+		{oct1, nil, false, false},
+		{signer, ecPriv, true, true},
+		{signer, signer, true, true},
+		{signer, signerOther, false, false},
+		{signer, ecPub, false, false},
+		{signer, signer.PublicKey(), false, false},
+		{signer, rsaPriv, false, false},
+		{signer, oct1, false, false},
+		{nil, nil, true, true},
+	}
+
+	for i, c := range cases {
+		if Equal(c.a, c.b) != c.equal || Equal(c.b, c.a) != c.equal {
+			t.Errorf("case %d: expected Equal to be %v in both orders", i, c.equal)
+		}
+		if EqualWithParams(c.a, c.b) != c.withParams || EqualWithParams(c.b, c.a) != c.withParams {
+			t.Errorf("case %d: expected EqualWithParams to be %v in both orders", i, c.withParams)
+		}
+	}
+}
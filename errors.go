@@ -0,0 +1,123 @@
+package jwk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ericyan/jwk/internal/base64url"
+)
+
+// Errors returned when creating or parsing keys. Parsing errors are
+// wrapped in a *ParseError, so use errors.Is to test for them.
+var (
+	ErrMalformed          = errors.New("jwk: malformed JSON")
+	ErrMissingMember      = errors.New("jwk: missing required member")
+	ErrInvalidMember      = errors.New("jwk: invalid member value")
+	ErrWrongKeyType       = errors.New("jwk: wrong key type")
+	ErrUnsupportedKeyType = errors.New("jwk: unsupported key type")
+	ErrUnsupportedCurve   = errors.New("jwk: unsupported curve")
+	ErrInvalidCryptoKey   = errors.New("jwk: invalid crypto key")
+	ErrDuplicateMember    = errors.New("jwk: duplicate member")
+	ErrTrailingData       = errors.New("jwk: trailing data")
+	ErrKeyTooLarge        = errors.New("jwk: key too large")
+	ErrTooManyKeys        = errors.New("jwk: too many keys")
+)
+
+// ParseError describes a problem with a JSON Web Key being parsed.
+type ParseError struct {
+	// Member is the name of the offending member, if known.
+	Member string
+
+	// Index is the position of the key in a JWK Set, or -1 if the key
+	// was not parsed as part of a set.
+	Index int
+
+	// Err is the underlying error, usually one of the sentinel errors
+	// of this package.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	b.WriteString("jwk: ")
+	if e.Index >= 0 {
+		b.WriteString("keys[" + strconv.Itoa(e.Index) + "]: ")
+	}
+	if e.Member != "" {
+		b.WriteString(e.Member + ": ")
+	}
+	b.WriteString(strings.TrimPrefix(e.Err.Error(), "jwk: "))
+
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseError returns a *ParseError for a key not in a set.
+func parseError(member string, err error) *ParseError {
+	return &ParseError{Member: member, Index: -1, Err: err}
+}
+
+// unmarshalError converts an error from json.Unmarshal into a
+// *ParseError, identifying the member where possible.
+func unmarshalError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		// Field is the full path, such as "key_ops.0" for an element of
+		// key_ops, but only the member is reported.
+		member, _, _ := strings.Cut(typeErr.Field, ".")
+		return parseError(member, fmt.Errorf("%w: %w", ErrInvalidMember, err))
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return parseError("", fmt.Errorf("%w: %w", ErrMalformed, err))
+	}
+
+	return parseError("", fmt.Errorf("%w: %w", ErrInvalidMember, err))
+}
+
+// unmarshalKey unmarshals data into key, converting errors as in
+// unmarshalError. As json.Unmarshal does not report the member for errors
+// returned by base64url.Value, the members named in values are decoded
+// one by one to find the offending member.
+func unmarshalKey(data []byte, key interface{}, values ...string) error {
+	err := json.Unmarshal(data, key)
+	if err == nil {
+		return nil
+	}
+
+	var members map[string]json.RawMessage
+	if json.Unmarshal(data, &members) == nil {
+		for _, name := range values {
+			raw, ok := members[name]
+			if !ok {
+				continue
+			}
+			if err := new(base64url.Value).UnmarshalJSON(raw); err != nil {
+				return parseError(name, fmt.Errorf("%w: %w", ErrInvalidMember, err))
+			}
+		}
+	}
+
+	return unmarshalError(err)
+}
+
+// inSet records the position of the key in a set on a parsing error.
+func inSet(err error, index int) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		e := *parseErr
+		e.Index = index
+		return &e
+	}
+
+	return &ParseError{Index: index, Err: err}
+}
@@ -0,0 +1,78 @@
+package jwk
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		jwk    string
+		member string
+		err    error
+	}{
+		{`{"kty":"EC","crv":"P-256","y":"AQAB"}`, "x", ErrMissingMember},
+		{`{"kty":"EC","crv":"P-256","x":"AQAB"}`, "y", ErrMissingMember},
+		{`{"kty":"EC","crv":"P-192","x":"AQAB","y":"AQAB"}`, "crv", ErrUnsupportedCurve},
+		{`{"kty":"RSA","e":"AQAB"}`, "n", ErrMissingMember},
+		{`{"kty":"RSA","n":"AQAB","e":"AQAB","d":"AQAB"}`, "p", ErrMissingMember},
+		{`{"kty":"oct"}`, "k", ErrMissingMember},
+		{`{"kty":"OKP","crv":"Ed25519","x":"AQAB"}`, "x", ErrInvalidMember},
+		{`{"kty":"DSA"}`, "kty", ErrUnsupportedKeyType},
+		{`{"kid":"foo"}`, "kty", ErrMissingMember},
+		{`{"kty":"EC","crv":1}`, "crv", ErrInvalidMember},
+		{`{"kty":"oct","k":"AQAB","key_ops":[1]}`, "key_ops", ErrInvalidMember},
+		{`{"kty":"EC","crv":"P-256","x":1,"y":"AA"}`, "x", ErrInvalidMember},
+		{`{"kty":"EC","crv":"P-256","x":"!!","y":"AA"}`, "x", ErrInvalidMember},
+		{`{"kty":"EC","crv":"P-256","x":"AA","y":"AA","d":"!!"}`, "d", ErrInvalidMember},
+		{`{"kty":"RSA","n":"AQAB","e":[]}`, "e", ErrInvalidMember},
+		{`{"kty":"RSA","n":"AQAB","e":"AQAB","d":"AQAB","p":"AQAB","q":"!!"}`, "q", ErrInvalidMember},
+		{`{"kty":"RSA","n":"AQAB","e":"AQAB","d":"AQAB","p":"AQAB","q":"AQAB"}`, "d", ErrInvalidMember},
+		{`{"kty":"oct","k":1}`, "k", ErrInvalidMember},
+		{`{"kty":"OKP","crv":"Ed25519","x":"!!"}`, "x", ErrInvalidMember},
+		{`{"kty":"EC"`, "", ErrMalformed},
+	}
+
+	for _, c := range cases {
+		_, err := Parse([]byte(c.jwk))
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: expected *ParseError, got %v", c.jwk, err)
+			continue
+		}
+		if parseErr.Member != c.member || parseErr.Index != -1 || !errors.Is(err, c.err) {
+			t.Errorf("%s: unexpected error %v", c.jwk, err)
+		}
+	}
+}
+
+func TestParseErrorWrongKeyType(t *testing.T) {
+	_, err := ParseRSAPublicKey([]byte(`{"kty":"EC"}`))
+	if !errors.Is(err, ErrWrongKeyType) {
+		t.Error("unexpected error:", err)
+	}
+
+	_, err = NewOctetSequenceKey([]byte("secret"), &Params{KeyType: TypeRSA})
+	if !errors.Is(err, ErrWrongKeyType) {
+		t.Error("unexpected error:", err)
+	}
+}
+
+func TestParseErrorInSet(t *testing.T) {
+	data := `{"keys":[{"kty":"oct","k":"AQAB"},{"kty":"EC","crv":"P-256","x":"AQAB"}]}`
+
+	err := json.Unmarshal([]byte(data), new(Set))
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatal("expected *ParseError, got", err)
+	}
+	if parseErr.Index != 1 || parseErr.Member != "y" || !errors.Is(err, ErrMissingMember) {
+		t.Error("unexpected error:", err)
+	}
+	if err.Error() != "jwk: keys[1]: y: missing required member" {
+		t.Error("unexpected error message:", err.Error())
+	}
+}
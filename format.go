@@ -0,0 +1,141 @@
+package jwk
+
+import (
+	"crypto"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// attrs returns the attributes of key that are safe to print: the key
+// type, key ID, algorithm and JWK Thumbprint. Secret material is never
+// included. Symmetric keys have no thumbprint attribute, as it is a hash
+// of the secret that allows guessing it offline.
+func attrs(key Key) []slog.Attr {
+	attrs := []slog.Attr{slog.String("kty", key.Type())}
+	if key.ID() != "" {
+		attrs = append(attrs, slog.String("kid", key.ID()))
+	}
+	if key.Alg() != "" {
+		attrs = append(attrs, slog.String("alg", key.Alg()))
+	}
+	attrs = append(attrs, slog.Bool("private", key.IsPrivate()))
+	if key.Type() == TypeOCT {
+		return attrs
+	}
+	if tp, err := Thumbprint(key, crypto.SHA256); err == nil {
+		attrs = append(attrs, slog.String("thumbprint", base64.RawURLEncoding.EncodeToString(tp)))
+	}
+
+	return attrs
+}
+
+// describe returns a description of key without secret material, such
+// as "ECDSAPrivateKey{kty: EC, kid: foo, private: true, ...}".
+func describe(key Key) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimPrefix(fmt.Sprintf("%T", key), "*jwk."))
+	b.WriteByte('{')
+	for i, attr := range attrs(key) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(attr.Key)
+		b.WriteString(": ")
+		b.WriteString(attr.Value.String())
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+// format writes the description of key for any verb, quoting it for %q.
+func format(f fmt.State, verb rune, key Key) {
+	s := describe(key)
+	if verb == 'q' {
+		s = strconv.Quote(s)
+	}
+	fmt.Fprint(f, s)
+}
+
+// String returns a description of the key.
+func (key *ECDSAPublicKey) String() string { return describe(key) }
+
+// Format implements the fmt.Formatter interface.
+func (key *ECDSAPublicKey) Format(f fmt.State, verb rune) { format(f, verb, key) }
+
+// LogValue implements the slog.LogValuer interface.
+func (key *ECDSAPublicKey) LogValue() slog.Value { return slog.GroupValue(attrs(key)...) }
+
+// String returns a description of the key without secret material.
+func (key *ECDSAPrivateKey) String() string { return describe(key) }
+
+// Format implements the fmt.Formatter interface. Secret material is
+// never printed, regardless of the verb and flags.
+func (key *ECDSAPrivateKey) Format(f fmt.State, verb rune) { format(f, verb, key) }
+
+// LogValue implements the slog.LogValuer interface. Secret material is
+// never logged.
+func (key *ECDSAPrivateKey) LogValue() slog.Value { return slog.GroupValue(attrs(key)...) }
+
+// String returns a description of the key.
+func (key *RSAPublicKey) String() string { return describe(key) }
+
+// Format implements the fmt.Formatter interface.
+func (key *RSAPublicKey) Format(f fmt.State, verb rune) { format(f, verb, key) }
+
+// LogValue implements the slog.LogValuer interface.
+func (key *RSAPublicKey) LogValue() slog.Value { return slog.GroupValue(attrs(key)...) }
+
+// String returns a description of the key without secret material.
+func (key *RSAPrivateKey) String() string { return describe(key) }
+
+// Format implements the fmt.Formatter interface. Secret material is
+// never printed, regardless of the verb and flags.
+func (key *RSAPrivateKey) Format(f fmt.State, verb rune) { format(f, verb, key) }
+
+// LogValue implements the slog.LogValuer interface. Secret material is
+// never logged.
+func (key *RSAPrivateKey) LogValue() slog.Value { return slog.GroupValue(attrs(key)...) }
+
+// String returns a description of the key.
+func (key *Ed25519PublicKey) String() string { return describe(key) }
+
+// Format implements the fmt.Formatter interface.
+func (key *Ed25519PublicKey) Format(f fmt.State, verb rune) { format(f, verb, key) }
+
+// LogValue implements the slog.LogValuer interface.
+func (key *Ed25519PublicKey) LogValue() slog.Value { return slog.GroupValue(attrs(key)...) }
+
+// String returns a description of the key without secret material.
+func (key *Ed25519PrivateKey) String() string { return describe(key) }
+
+// Format implements the fmt.Formatter interface. Secret material is
+// never printed, regardless of the verb and flags.
+func (key *Ed25519PrivateKey) Format(f fmt.State, verb rune) { format(f, verb, key) }
+
+// LogValue implements the slog.LogValuer interface. Secret material is
+// never logged.
+func (key *Ed25519PrivateKey) LogValue() slog.Value { return slog.GroupValue(attrs(key)...) }
+
+// String returns a description of the key without secret material.
+func (key *OctetSequenceKey) String() string { return describe(key) }
+
+// Format implements the fmt.Formatter interface. Secret material is
+// never printed, regardless of the verb and flags.
+func (key *OctetSequenceKey) Format(f fmt.State, verb rune) { format(f, verb, key) }
+
+// LogValue implements the slog.LogValuer interface. Secret material is
+// never logged.
+func (key *OctetSequenceKey) LogValue() slog.Value { return slog.GroupValue(attrs(key)...) }
+
+// String returns a description of the key.
+func (key *SignerKey) String() string { return describe(key) }
+
+// Format implements the fmt.Formatter interface.
+func (key *SignerKey) Format(f fmt.State, verb rune) { format(f, verb, key) }
+
+// LogValue implements the slog.LogValuer interface.
+func (key *SignerKey) LogValue() slog.Value { return slog.GroupValue(attrs(key)...) }
@@ -0,0 +1,77 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func b64(v interface{ Bytes() []byte }) string {
+	return base64.RawURLEncoding.EncodeToString(v.Bytes())
+}
+
+func TestFormatRedacted(t *testing.T) {
+	ec, _ := NewECDSAPrivateKey(ecdsaTestKeyP256, &Params{KeyID: "ec", Algorithm: "ES256"})
+	rsa, _ := NewRSAPrivateKey(rsaTestKey, &Params{KeyID: "rsa"})
+	ed, _ := NewEd25519PrivateKey(ed25519TestKey, &Params{KeyID: "ed"})
+	oct, _ := NewOctetSequenceKey([]byte("super secret key material"), &Params{KeyID: "oct"})
+	octThumbprint, _ := Thumbprint(oct, crypto.SHA256)
+
+	for _, c := range []struct {
+		key        Key
+		secrets    []string
+		thumbprint bool
+	}{
+		{ec, []string{b64(ec.D)}, true},
+		{rsa, []string{b64(rsa.D), b64(rsa.P), b64(rsa.Q)}, true},
+		{ed, []string{b64(ed.D)}, true},
+		{oct, []string{b64(oct.K), "super secret", base64.RawURLEncoding.EncodeToString(octThumbprint)}, false},
+	} {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		logger.Info("key", "key", c.key)
+
+		outputs := []string{buf.String(), fmt.Sprint(c.key)}
+		for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x"} {
+			outputs = append(outputs, fmt.Sprintf(verb, c.key))
+		}
+
+		for _, out := range outputs {
+			if !strings.Contains(out, c.key.ID()) || strings.Contains(out, "thumbprint") != c.thumbprint {
+				t.Errorf("%s: unexpected key attributes in %q", c.key.ID(), out)
+			}
+			for _, secret := range c.secrets {
+				if strings.Contains(out, secret) {
+					t.Errorf("%s: secret material in %q", c.key.ID(), out)
+				}
+			}
+		}
+	}
+}
+
+func TestFormatPublicKey(t *testing.T) {
+	key, _ := NewECDSAPublicKey(&ecdsaTestKeyP256.PublicKey, &Params{KeyID: "foo", Algorithm: "ES256"})
+
+	s := key.String()
+	if !strings.HasPrefix(s, "ECDSAPublicKey{kty: EC, kid: foo, alg: ES256, private: false, thumbprint: ") {
+		t.Error("unexpected description:", s)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("msg", "key", key)
+
+	var entry struct {
+		Key map[string]interface{} `json:"key"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Key["kty"] != "EC" || entry.Key["kid"] != "foo" || entry.Key["private"] != false {
+		t.Error("unexpected log value:", entry.Key)
+	}
+}
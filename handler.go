@@ -0,0 +1,104 @@
+package jwk
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MediaTypeSet is the media type of a JSON Web Key Set, as registered in
+// RFC 7517, Section 8.5.
+const MediaTypeSet = "application/jwk-set+json"
+
+// Provider supplies a Set on demand.
+type Provider interface {
+	KeySet() (*Set, error)
+}
+
+// ProviderFunc is an adapter to allow the use of ordinary functions as
+// Providers.
+type ProviderFunc func() (*Set, error)
+
+// KeySet calls f().
+func (f ProviderFunc) KeySet() (*Set, error) {
+	return f()
+}
+
+// Handler serves the public keys of a Set as a JSON Web Key Set. Private
+// members are never served: private keys are served as their public
+// counterparts and symmetric keys are omitted.
+//
+// Handler implements the http.Handler interface.
+type Handler struct {
+	Provider Provider
+	MaxAge   time.Duration
+}
+
+// NewHandler creates a new Handler serving a static Set.
+func NewHandler(set *Set, maxAge time.Duration) *Handler {
+	return &Handler{
+		Provider: ProviderFunc(func() (*Set, error) { return set, nil }),
+		MaxAge:   maxAge,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	set, err := h.Provider.KeySet()
+	if err != nil || set == nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	body, err := json.Marshal(set.Public())
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+
+	header := w.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.MaxAge/time.Second)))
+
+	if etagMatch(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", MediaTypeSet)
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(body)
+}
+
+// etagMatch reports whether the If-None-Match header value matches etag,
+// using the weak comparison function defined in RFC 7232, Section 2.3.2.
+func etagMatch(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package jwk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	ecKey, _ := NewECDSAPrivateKey(ecdsaTestKeyP256, &Params{KeyID: "ec"})
+	rsaKey, _ := NewRSAPrivateKey(rsaTestKey, &Params{KeyID: "rsa"})
+	octKey, _ := NewOctetSequenceKey([]byte{1, 2, 3, 4}, &Params{KeyID: "oct"})
+	h := NewHandler(&Set{Keys: []Key{ecKey, rsaKey, octKey}}, time.Hour)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jwks.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatal("unexpected status:", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != MediaTypeSet {
+		t.Error("unexpected content type:", ct)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=3600" {
+		t.Error("unexpected cache control:", cc)
+	}
+
+	var members struct {
+		Keys []map[string]interface{} `json:"keys"`
+	}
+	err := json.Unmarshal(rec.Body.Bytes(), &members)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(members.Keys) != 2 {
+		t.Fatal("expected symmetric key to be omitted, got keys:", len(members.Keys))
+	}
+	for _, m := range members.Keys {
+		for _, name := range []string{"d", "p", "q", "dp", "dq", "qi", "k"} {
+			if _, ok := m[name]; ok {
+				t.Errorf("private member '%s' served for key '%s'", name, m["kid"])
+			}
+		}
+	}
+
+	var set Set
+	err = json.Unmarshal(rec.Body.Bytes(), &set)
+	if err != nil {
+		t.Fatal("served key set cannot be parsed:", err)
+	}
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag")
+	}
+	for _, inm := range []string{etag, "W/" + etag, `"foo", ` + etag, "*"} {
+		req := httptest.NewRequest(http.MethodGet, "/jwks.json", nil)
+		req.Header.Set("If-None-Match", inm)
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("expected 304 for If-None-Match '%s', got %d", inm, rec.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/jwks.json", nil)
+	req.Header.Set("If-None-Match", `"foo"`)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Error("expected 200 for stale ETag, got", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jwks.json", strings.NewReader("")))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Error("expected 405 for POST, got", rec.Code)
+	}
+}
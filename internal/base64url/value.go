@@ -52,6 +52,17 @@ func (val *Value) Uint64() uint64 {
 	return val.BigInt().Uint64()
 }
 
+// Zero overwrites the octets with zeros.
+func (val *Value) Zero() {
+	if val == nil {
+		return
+	}
+
+	for i := range val.octets {
+		val.octets[i] = 0
+	}
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 func (val *Value) MarshalJSON() ([]byte, error) {
 	return json.Marshal(base64.RawURLEncoding.EncodeToString(val.octets))
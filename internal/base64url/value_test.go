@@ -68,3 +68,18 @@ func TestZero(t *testing.T) {
 		t.Error("zero values are not equal")
 	}
 }
+
+func TestValueZero(t *testing.T) {
+	data := []byte{1, 2, 3}
+	val := NewValue(data)
+	val.Zero()
+
+	for _, b := range data {
+		if b != 0 {
+			t.Fatal("octets not zeroed:", data)
+		}
+	}
+
+	var nilValue *Value
+	nilValue.Zero()
+}
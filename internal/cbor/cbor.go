@@ -0,0 +1,285 @@
+// Package cbor implements the subset of CBOR (RFC 8949) needed to handle
+// COSE keys and WebAuthn structures: integers, byte and text strings,
+// arrays, maps, booleans and null, all with definite lengths.
+package cbor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// Major types defined in RFC 8949, Section 3.1.
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+)
+
+// Simple values defined in RFC 8949, Section 3.3.
+const (
+	simpleFalse = 20
+	simpleTrue  = 21
+	simpleNull  = 22
+)
+
+// maxDepth limits the nesting of arrays and maps when decoding.
+const maxDepth = 16
+
+// Map is a CBOR map. Keys are either int64 or string.
+type Map map[interface{}]interface{}
+
+// Marshal returns the deterministic CBOR encoding (RFC 8949, Section
+// 4.2.1) of v, which must be composed of int, int64, []byte, string,
+// bool, nil, []interface{} and Map values.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encode(&buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encode(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case int:
+		return encode(buf, int64(v))
+	case int64:
+		if v < 0 {
+			writeHead(buf, majorNegInt, uint64(-1-v))
+		} else {
+			writeHead(buf, majorUint, uint64(v))
+		}
+	case []byte:
+		writeHead(buf, majorBytes, uint64(len(v)))
+		buf.Write(v)
+	case string:
+		writeHead(buf, majorText, uint64(len(v)))
+		buf.WriteString(v)
+	case bool:
+		if v {
+			buf.WriteByte(majorSimple<<5 | simpleTrue)
+		} else {
+			buf.WriteByte(majorSimple<<5 | simpleFalse)
+		}
+	case nil:
+		buf.WriteByte(majorSimple<<5 | simpleNull)
+	case []interface{}:
+		writeHead(buf, majorArray, uint64(len(v)))
+		for _, item := range v {
+			if err := encode(buf, item); err != nil {
+				return err
+			}
+		}
+	case Map:
+		type entry struct{ key, value []byte }
+		entries := make([]entry, 0, len(v))
+		for key, value := range v {
+			switch key.(type) {
+			case int, int64, string:
+			default:
+				return errors.New("cbor: unsupported map key type")
+			}
+
+			var k, val bytes.Buffer
+			if err := encode(&k, key); err != nil {
+				return err
+			}
+			if err := encode(&val, value); err != nil {
+				return err
+			}
+			entries = append(entries, entry{k.Bytes(), val.Bytes()})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].key, entries[j].key) < 0
+		})
+
+		writeHead(buf, majorMap, uint64(len(entries)))
+		for i, e := range entries {
+			if i > 0 && bytes.Equal(e.key, entries[i-1].key) {
+				return errors.New("cbor: duplicate map key")
+			}
+			buf.Write(e.key)
+			buf.Write(e.value)
+		}
+	default:
+		return errors.New("cbor: unsupported type")
+	}
+
+	return nil
+}
+
+func writeHead(buf *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		buf.WriteByte(major<<5 | byte(n))
+	case n <= math.MaxUint8:
+		buf.Write([]byte{major<<5 | 24, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(major<<5 | 25)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n <= math.MaxUint32:
+		buf.WriteByte(major<<5 | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		buf.WriteByte(major<<5 | 27)
+		buf.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+// Unmarshal decodes a single CBOR data item, which must span all of data.
+func Unmarshal(data []byte) (interface{}, error) {
+	v, rest, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("cbor: trailing data")
+	}
+
+	return v, nil
+}
+
+// Decode decodes the CBOR data item at the beginning of data and returns
+// the remaining bytes. Integers are decoded as int64, byte strings as
+// []byte, text strings as string, arrays as []interface{} and maps as
+// Map. Tags, floating-point numbers and indefinite lengths are rejected.
+func Decode(data []byte) (interface{}, []byte, error) {
+	return decode(data, 0)
+}
+
+func decode(data []byte, depth int) (interface{}, []byte, error) {
+	if depth > maxDepth {
+		return nil, nil, errors.New("cbor: nesting too deep")
+	}
+
+	major, n, data, err := readHead(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case majorUint:
+		if n > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return int64(n), data, nil
+	case majorNegInt:
+		if n > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return -1 - int64(n), data, nil
+	case majorBytes, majorText:
+		if n > uint64(len(data)) {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		if major == majorText {
+			if !utf8.Valid(data[:n]) {
+				return nil, nil, errors.New("cbor: invalid UTF-8 in text string")
+			}
+			return string(data[:n]), data[n:], nil
+		}
+		return append([]byte{}, data[:n]...), data[n:], nil
+	case majorArray:
+		// Every item takes at least one byte.
+		if n > uint64(len(data)) {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		arr := make([]interface{}, n)
+		for i := range arr {
+			arr[i], data, err = decode(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		return arr, data, nil
+	case majorMap:
+		if n > uint64(len(data))/2 {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		m := make(Map, n)
+		for i := uint64(0); i < n; i++ {
+			var key, value interface{}
+			key, data, err = decode(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, errors.New("cbor: unsupported map key type")
+			}
+			if _, ok := m[key]; ok {
+				return nil, nil, errors.New("cbor: duplicate map key")
+			}
+
+			value, data, err = decode(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			m[key] = value
+		}
+		return m, data, nil
+	case majorSimple:
+		switch n {
+		case simpleFalse:
+			return false, data, nil
+		case simpleTrue:
+			return true, data, nil
+		case simpleNull:
+			return nil, data, nil
+		}
+		return nil, nil, errors.New("cbor: unsupported simple value")
+	default:
+		return nil, nil, errors.New("cbor: unsupported major type")
+	}
+}
+
+func readHead(data []byte) (byte, uint64, []byte, error) {
+	if len(data) == 0 {
+		return 0, 0, nil, errors.New("cbor: unexpected end of data")
+	}
+
+	major, info := data[0]>>5, data[0]&0x1f
+	data = data[1:]
+
+	if major == majorSimple && info > 24 {
+		return 0, 0, nil, errors.New("cbor: unsupported simple value")
+	}
+
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info), data, nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, 0, nil, errors.New("cbor: indefinite length not supported")
+	}
+
+	if len(data) < size {
+		return 0, 0, nil, errors.New("cbor: unexpected end of data")
+	}
+
+	var n uint64
+	for _, b := range data[:size] {
+		n = n<<8 | uint64(b)
+	}
+
+	return major, n, data[size:], nil
+}
@@ -0,0 +1,106 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+// Examples from RFC 8949, Appendix A.
+var cborTestCases = []struct {
+	value   interface{}
+	encoded string
+}{
+	{int64(0), "00"},
+	{int64(23), "17"},
+	{int64(24), "1818"},
+	{int64(1000000), "1a000f4240"},
+	{int64(1000000000000), "1b000000e8d4a51000"},
+	{int64(-1), "20"},
+	{int64(-1000), "3903e7"},
+	{[]byte{1, 2, 3, 4}, "4401020304"},
+	{"IETF", "6449455446"},
+	{"ü", "62c3bc"},
+	{false, "f4"},
+	{true, "f5"},
+	{nil, "f6"},
+	{[]interface{}{int64(1), []interface{}{int64(2), int64(3)}}, "8201820203"},
+	{Map{int64(1): int64(2), int64(3): int64(4)}, "a201020304"},
+	{Map{"a": int64(1), "b": []interface{}{int64(2), int64(3)}}, "a26161016162820203"},
+}
+
+func TestMarshal(t *testing.T) {
+	for _, tc := range cborTestCases {
+		encoded, err := Marshal(tc.value)
+		if err != nil {
+			t.Errorf("failed to marshal %v: %s", tc.value, err)
+			continue
+		}
+		if hex.EncodeToString(encoded) != tc.encoded {
+			t.Errorf("marshal %v: got %x, want %s", tc.value, encoded, tc.encoded)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	for _, tc := range cborTestCases {
+		data, _ := hex.DecodeString(tc.encoded)
+		v, err := Unmarshal(data)
+		if err != nil {
+			t.Errorf("failed to unmarshal %s: %s", tc.encoded, err)
+			continue
+		}
+		if !reflect.DeepEqual(v, tc.value) {
+			t.Errorf("unmarshal %s: got %#v, want %#v", tc.encoded, v, tc.value)
+		}
+	}
+}
+
+func TestMarshalDeterministic(t *testing.T) {
+	// Keys sort by their encoding: 10, -1, "z", "aa".
+	m := Map{"aa": int64(4), "z": int64(3), int64(-1): int64(2), int64(10): int64(1)}
+	encoded, err := Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := hex.DecodeString("a40a012002617a0362616104")
+	if !bytes.Equal(encoded, expected) {
+		t.Errorf("got %x, want %x", encoded, expected)
+	}
+}
+
+func TestDecodeRest(t *testing.T) {
+	v, rest, err := Decode([]byte{0x01, 0x02})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != int64(1) || !bytes.Equal(rest, []byte{0x02}) {
+		t.Errorf("unexpected result %v, %x", v, rest)
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",                                     // empty
+		"0102",                                 // trailing data
+		"18",                                   // truncated head
+		"4401",                                 // truncated byte string
+		"62c3",                                 // truncated text string
+		"62ff00",                               // invalid UTF-8
+		"5f",                                   // indefinite length
+		"c100",                                 // tag
+		"f93c00",                               // float
+		"a201020103",                           // duplicate map key
+		"a14101f6",                             // byte string map key
+		"1bffffffffffffffff",                   // integer overflow
+		"9b7fffffffffffffff",                   // oversized array
+		"818181818181818181818181818181818100", // nesting too deep
+	} {
+		data, _ := hex.DecodeString(s)
+		if _, err := Unmarshal(data); err == nil {
+			t.Errorf("expected error on unmarshaling %q", s)
+		}
+	}
+}
@@ -0,0 +1,486 @@
+package jwe
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1" // register SHA-1 for RSA-OAEP
+	"crypto/sha256"
+	_ "crypto/sha512" // register SHA-384 and SHA-512 for PBES2
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ericyan/jwk"
+)
+
+// Key management algorithms defined in RFC 7518, Section 4.1.
+const (
+	RSAOAEP      = "RSA-OAEP"
+	RSAOAEP256   = "RSA-OAEP-256"
+	A128KW       = "A128KW"
+	A192KW       = "A192KW"
+	A256KW       = "A256KW"
+	Direct       = "dir"
+	ECDHES       = "ECDH-ES"
+	ECDHESA128KW = "ECDH-ES+A128KW"
+	ECDHESA192KW = "ECDH-ES+A192KW"
+	ECDHESA256KW = "ECDH-ES+A256KW"
+
+	PBES2HS256A128KW = "PBES2-HS256+A128KW"
+	PBES2HS384A192KW = "PBES2-HS384+A192KW"
+	PBES2HS512A256KW = "PBES2-HS512+A256KW"
+)
+
+// PBES2 iteration counts. DefaultPBES2Count is used when encrypting if
+// the p2c header parameter is not set. Messages with a count outside of
+// the bounds are rejected, as the count is chosen by the sender and
+// determines the cost of decryption.
+const (
+	DefaultPBES2Count = 600000
+	minPBES2Count     = 1000
+	maxPBES2Count     = 1000000
+)
+
+// minRSAKeySize is the minimum RSA key size in bits required by RFC 7518,
+// Sections 4.2 and 4.3.
+const minRSAKeySize = 2048
+
+// keyManagement describes a key management algorithm.
+type keyManagement struct {
+	kty      string
+	hash     crypto.Hash // hash function for RSA-OAEP and PBES2
+	wrapSize int         // size of the AES key wrap key, 0 if not used
+	ecdh     bool
+	pbes2    bool
+}
+
+var keyManagements = map[string]keyManagement{
+	RSAOAEP:      {kty: jwk.TypeRSA, hash: crypto.SHA1},
+	RSAOAEP256:   {kty: jwk.TypeRSA, hash: crypto.SHA256},
+	A128KW:       {kty: jwk.TypeOCT, wrapSize: 16},
+	A192KW:       {kty: jwk.TypeOCT, wrapSize: 24},
+	A256KW:       {kty: jwk.TypeOCT, wrapSize: 32},
+	Direct:       {kty: jwk.TypeOCT},
+	ECDHES:       {kty: jwk.TypeEC, ecdh: true},
+	ECDHESA128KW: {kty: jwk.TypeEC, ecdh: true, wrapSize: 16},
+	ECDHESA192KW: {kty: jwk.TypeEC, ecdh: true, wrapSize: 24},
+	ECDHESA256KW: {kty: jwk.TypeEC, ecdh: true, wrapSize: 32},
+
+	PBES2HS256A128KW: {kty: jwk.TypeOCT, hash: crypto.SHA256, wrapSize: 16, pbes2: true},
+	PBES2HS384A192KW: {kty: jwk.TypeOCT, hash: crypto.SHA384, wrapSize: 24, pbes2: true},
+	PBES2HS512A256KW: {kty: jwk.TypeOCT, hash: crypto.SHA512, wrapSize: 32, pbes2: true},
+}
+
+// lookupKeyManagement returns the key management algorithm named alg
+// after checking that key is allowed to be used with it.
+func lookupKeyManagement(alg string, key jwk.Key) (keyManagement, error) {
+	km, ok := keyManagements[alg]
+	if !ok {
+		return km, fmt.Errorf("jwe: unsupported key management algorithm '%s'", alg)
+	}
+	if key == nil {
+		return km, errors.New("jwe: missing key")
+	}
+	if key.Type() != km.kty {
+		return km, fmt.Errorf("jwe: key type '%s' cannot be used with algorithm '%s'", key.Type(), alg)
+	}
+	if key.Alg() != "" && key.Alg() != alg {
+		return km, fmt.Errorf("jwe: key is restricted to algorithm '%s'", key.Alg())
+	}
+	if km.pbes2 && key.Alg() != alg {
+		// A password is not an ordinary symmetric key, and PBES2 lets the
+		// sender choose the cost of decryption.
+		return km, fmt.Errorf("jwe: key must be restricted to algorithm '%s'", alg)
+	}
+	if use := key.Parameters().KeyUse; use != "" && use != "enc" {
+		return km, fmt.Errorf("jwe: key is intended for use '%s'", use)
+	}
+
+	return km, nil
+}
+
+// checkKeyOps checks that the key operations of key permit one of ops.
+func checkKeyOps(key jwk.Key, ops ...string) error {
+	keyOps := key.Parameters().KeyOps
+	if len(keyOps) == 0 {
+		return nil
+	}
+
+	for _, op := range ops {
+		for _, keyOp := range keyOps {
+			if op == keyOp {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("jwe: key does not permit operation '%s'", ops[0])
+}
+
+// wrapKey determines the content encryption key for the recipient key
+// and returns it along with the JWE Encrypted Key. Algorithm specific
+// header parameters are set in h.
+func wrapKey(h *Header, key jwk.Key, enc contentCipher) ([]byte, []byte, error) {
+	km, err := lookupKeyManagement(h.Algorithm, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case km.kty == jwk.TypeRSA:
+		if err := checkKeyOps(key, "wrapKey", "encrypt"); err != nil {
+			return nil, nil, err
+		}
+		pub, ok := publicKey(key).(*rsa.PublicKey)
+		if !ok {
+			return nil, nil, errors.New("jwe: unsupported crypto key")
+		}
+		if pub.N.BitLen() < minRSAKeySize {
+			return nil, nil, errors.New("jwe: RSA key too small")
+		}
+
+		cek, err := randomBytes(enc.keySize)
+		if err != nil {
+			return nil, nil, err
+		}
+		encryptedKey, err := rsa.EncryptOAEP(km.hash.New(), rand.Reader, pub, cek, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return cek, encryptedKey, nil
+	case km.ecdh:
+		if err := checkKeyOps(key, "deriveKey", "wrapKey", "encrypt"); err != nil {
+			return nil, nil, err
+		}
+		pub, ok := publicKey(key).(*ecdsa.PublicKey)
+		if !ok {
+			return nil, nil, errors.New("jwe: unsupported crypto key")
+		}
+		recipient, err := pub.ECDH()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ephemeral, err := recipient.Curve().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		epk, err := marshalEphemeralKey(pub.Curve, ephemeral.PublicKey())
+		if err != nil {
+			return nil, nil, err
+		}
+		h.EphemeralPublicKey = epk
+
+		z, err := ephemeral.ECDH(recipient)
+		if err != nil {
+			return nil, nil, err
+		}
+		derived, err := deriveKey(h, km, enc, z)
+		if err != nil {
+			return nil, nil, err
+		}
+		if km.wrapSize == 0 {
+			return derived, nil, nil
+		}
+
+		cek, err := randomBytes(enc.keySize)
+		if err != nil {
+			return nil, nil, err
+		}
+		encryptedKey, err := keyWrap(derived, cek)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return cek, encryptedKey, nil
+	case km.wrapSize > 0:
+		if err := checkKeyOps(key, "wrapKey"); err != nil {
+			return nil, nil, err
+		}
+
+		var kek []byte
+		if km.pbes2 {
+			if h.PBES2Count == 0 {
+				h.PBES2Count = DefaultPBES2Count
+			}
+			salt, err := randomBytes(16)
+			if err != nil {
+				return nil, nil, err
+			}
+			h.PBES2SaltInput = encode(salt)
+
+			kek, err = derivePBES2Key(h, km, key)
+		} else {
+			kek, err = secretKey(key, km.wrapSize)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cek, err := randomBytes(enc.keySize)
+		if err != nil {
+			return nil, nil, err
+		}
+		encryptedKey, err := keyWrap(kek, cek)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return cek, encryptedKey, nil
+	default: // dir
+		if err := checkKeyOps(key, "encrypt"); err != nil {
+			return nil, nil, err
+		}
+		cek, err := secretKey(key, enc.keySize)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return cek, nil, nil
+	}
+}
+
+// unwrapKey recovers the content encryption key from the JWE Encrypted
+// Key using the recipient key.
+func unwrapKey(h *Header, key jwk.Key, enc contentCipher, encryptedKey []byte) ([]byte, error) {
+	km, err := lookupKeyManagement(h.Algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var cek []byte
+	switch {
+	case km.kty == jwk.TypeRSA:
+		if err := checkKeyOps(key, "unwrapKey", "decrypt"); err != nil {
+			return nil, err
+		}
+		decrypter, ok := key.CryptoKey().(crypto.Decrypter)
+		if !ok {
+			return nil, errors.New("jwe: key cannot be used for decryption")
+		}
+		if pub, ok := decrypter.Public().(*rsa.PublicKey); !ok || pub.N.BitLen() < minRSAKeySize {
+			return nil, errors.New("jwe: RSA key too small")
+		}
+
+		cek, err = decrypter.Decrypt(rand.Reader, encryptedKey, &rsa.OAEPOptions{Hash: km.hash})
+		if err != nil {
+			return nil, ErrDecryption
+		}
+	case km.ecdh:
+		if err := checkKeyOps(key, "deriveKey", "unwrapKey", "decrypt"); err != nil {
+			return nil, err
+		}
+		priv, ok := key.CryptoKey().(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("jwe: key cannot be used for decryption")
+		}
+		recipient, err := priv.ECDH()
+		if err != nil {
+			return nil, err
+		}
+
+		epk, err := parseEphemeralKey(h.EphemeralPublicKey, priv.Curve)
+		if err != nil {
+			return nil, err
+		}
+		z, err := recipient.ECDH(epk)
+		if err != nil {
+			return nil, ErrDecryption
+		}
+		derived, err := deriveKey(h, km, enc, z)
+		if err != nil {
+			return nil, err
+		}
+		if km.wrapSize == 0 {
+			if len(encryptedKey) != 0 {
+				return nil, errors.New("jwe: unexpected encrypted key")
+			}
+			return derived, nil
+		}
+
+		cek, err = keyUnwrap(derived, encryptedKey)
+		if err != nil {
+			return nil, err
+		}
+	case km.wrapSize > 0:
+		if err := checkKeyOps(key, "unwrapKey"); err != nil {
+			return nil, err
+		}
+
+		var kek []byte
+		if km.pbes2 {
+			kek, err = derivePBES2Key(h, km, key)
+		} else {
+			kek, err = secretKey(key, km.wrapSize)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		cek, err = keyUnwrap(kek, encryptedKey)
+		if err != nil {
+			return nil, err
+		}
+	default: // dir
+		if err := checkKeyOps(key, "decrypt"); err != nil {
+			return nil, err
+		}
+		if len(encryptedKey) != 0 {
+			return nil, errors.New("jwe: unexpected encrypted key")
+		}
+
+		return secretKey(key, enc.keySize)
+	}
+
+	if len(cek) != enc.keySize {
+		return nil, ErrDecryption
+	}
+
+	return cek, nil
+}
+
+// deriveKey derives a key from the shared secret z using the Concat KDF,
+// as defined in RFC 7518, Section 4.6.2.
+func deriveKey(h *Header, km keyManagement, enc contentCipher, z []byte) ([]byte, error) {
+	algID, keySize := h.Encryption, enc.keySize
+	if km.wrapSize > 0 {
+		algID, keySize = h.Algorithm, km.wrapSize
+	}
+
+	apu, err := decode(h.AgreementPartyUInfo)
+	if err != nil {
+		return nil, fmt.Errorf("jwe: invalid apu: %v", err)
+	}
+	apv, err := decode(h.AgreementPartyVInfo)
+	if err != nil {
+		return nil, fmt.Errorf("jwe: invalid apv: %v", err)
+	}
+
+	var otherInfo []byte
+	for _, field := range [][]byte{[]byte(algID), apu, apv} {
+		otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(len(field)))
+		otherInfo = append(otherInfo, field...)
+	}
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(keySize*8))
+
+	var derived []byte
+	for counter := uint32(1); len(derived) < keySize; counter++ {
+		d := sha256.New()
+		d.Write(binary.BigEndian.AppendUint32(nil, counter))
+		d.Write(z)
+		d.Write(otherInfo)
+		derived = d.Sum(derived)
+	}
+
+	return derived[:keySize], nil
+}
+
+// derivePBES2Key derives the key encryption key from the password held
+// by key, as defined in RFC 7518, Section 4.8.1.1.
+func derivePBES2Key(h *Header, km keyManagement, key jwk.Key) ([]byte, error) {
+	password, ok := key.CryptoKey().([]byte)
+	if !ok {
+		return nil, errors.New("jwe: unsupported crypto key")
+	}
+	if h.PBES2Count < minPBES2Count || h.PBES2Count > maxPBES2Count {
+		return nil, fmt.Errorf("jwe: unsupported p2c %d", h.PBES2Count)
+	}
+
+	saltInput, err := decode(h.PBES2SaltInput)
+	if err != nil {
+		return nil, fmt.Errorf("jwe: invalid p2s: %v", err)
+	}
+	if len(saltInput) < 8 {
+		return nil, errors.New("jwe: invalid p2s, too short")
+	}
+
+	salt := make([]byte, 0, len(h.Algorithm)+1+len(saltInput))
+	salt = append(salt, h.Algorithm...)
+	salt = append(salt, 0)
+	salt = append(salt, saltInput...)
+
+	return pbkdf2.Key(km.hash.New, string(password), salt, h.PBES2Count, km.wrapSize)
+}
+
+// marshalEphemeralKey returns the JSON Web Key of the ephemeral public
+// key.
+func marshalEphemeralKey(curve elliptic.Curve, pub *ecdh.PublicKey) ([]byte, error) {
+	point := pub.Bytes() // uncompressed form
+	size := (len(point) - 1) / 2
+	key, err := jwk.NewECDSAPublicKey(&ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(point[1 : 1+size]),
+		Y:     new(big.Int).SetBytes(point[1+size:]),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(key)
+}
+
+// parseEphemeralKey parses the ephemeral public key, which must be on
+// curve.
+func parseEphemeralKey(data []byte, curve elliptic.Curve) (*ecdh.PublicKey, error) {
+	if len(data) == 0 {
+		return nil, errors.New("jwe: missing epk")
+	}
+
+	key, err := jwk.ParseECDSAPublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("jwe: invalid epk: %v", err)
+	}
+	pub := key.CryptoKey().(*ecdsa.PublicKey)
+	if pub.Curve != curve {
+		return nil, errors.New("jwe: invalid epk, wrong elliptic curve")
+	}
+
+	epk, err := pub.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("jwe: invalid epk: %v", err)
+	}
+
+	return epk, nil
+}
+
+// publicKey returns the public key of key.
+func publicKey(key jwk.Key) crypto.PublicKey {
+	pub := key.CryptoKey()
+	if signer, ok := pub.(crypto.Signer); ok {
+		return signer.Public()
+	}
+
+	return pub
+}
+
+// secretKey returns the symmetric key of key, which must be size octets.
+func secretKey(key jwk.Key, size int) ([]byte, error) {
+	secret, ok := key.CryptoKey().([]byte)
+	if !ok {
+		return nil, errors.New("jwe: unsupported crypto key")
+	}
+	if len(secret) != size {
+		return nil, errors.New("jwe: invalid key size for algorithm")
+	}
+
+	return secret, nil
+}
+
+// randomBytes returns n random octets.
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
@@ -0,0 +1,163 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// Content encryption algorithms defined in RFC 7518, Section 5.1.
+const (
+	A128CBCHS256 = "A128CBC-HS256"
+	A192CBCHS384 = "A192CBC-HS384"
+	A256CBCHS512 = "A256CBC-HS512"
+	A128GCM      = "A128GCM"
+	A192GCM      = "A192GCM"
+	A256GCM      = "A256GCM"
+)
+
+// contentCipher describes a content encryption algorithm.
+type contentCipher struct {
+	keySize int
+	ivSize  int
+	hash    func() hash.Hash // nil for AES GCM
+}
+
+var contentCiphers = map[string]contentCipher{
+	A128CBCHS256: {keySize: 32, ivSize: 16, hash: sha256.New},
+	A192CBCHS384: {keySize: 48, ivSize: 16, hash: sha512.New384},
+	A256CBCHS512: {keySize: 64, ivSize: 16, hash: sha512.New},
+	A128GCM:      {keySize: 16, ivSize: 12},
+	A192GCM:      {keySize: 24, ivSize: 12},
+	A256GCM:      {keySize: 32, ivSize: 12},
+}
+
+// lookupContentCipher returns the content encryption algorithm named enc.
+func lookupContentCipher(enc string) (contentCipher, error) {
+	c, ok := contentCiphers[enc]
+	if !ok {
+		return c, fmt.Errorf("jwe: unsupported content encryption algorithm '%s'", enc)
+	}
+
+	return c, nil
+}
+
+// seal encrypts and authenticates plaintext and authenticates aad,
+// returning the ciphertext and the authentication tag.
+func (c contentCipher) seal(cek, iv, plaintext, aad []byte) ([]byte, []byte, error) {
+	if len(cek) != c.keySize || len(iv) != c.ivSize {
+		return nil, nil, errors.New("jwe: invalid key or IV size")
+	}
+
+	if c.hash == nil {
+		aead, err := newGCM(cek)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sealed := aead.Seal(nil, iv, plaintext, aad)
+		n := len(sealed) - aead.Overhead()
+		return sealed[:n], sealed[n:], nil
+	}
+
+	macKey, encKey := cek[:len(cek)/2], cek[len(cek)/2:]
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := make([]byte, len(plaintext)+padding)
+	copy(ciphertext, plaintext)
+	for i := len(plaintext); i < len(ciphertext); i++ {
+		ciphertext[i] = byte(padding)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return ciphertext, c.cbcTag(macKey, iv, ciphertext, aad), nil
+}
+
+// open authenticates and decrypts ciphertext and authenticates aad.
+func (c contentCipher) open(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	if len(cek) != c.keySize || len(iv) != c.ivSize {
+		return nil, ErrDecryption
+	}
+
+	if c.hash == nil {
+		aead, err := newGCM(cek)
+		if err != nil {
+			return nil, err
+		}
+
+		sealed := make([]byte, 0, len(ciphertext)+len(tag))
+		sealed = append(sealed, ciphertext...)
+		sealed = append(sealed, tag...)
+		plaintext, err := aead.Open(nil, iv, sealed, aad)
+		if err != nil {
+			return nil, ErrDecryption
+		}
+
+		return plaintext, nil
+	}
+
+	macKey, encKey := cek[:len(cek)/2], cek[len(cek)/2:]
+	if !hmac.Equal(tag, c.cbcTag(macKey, iv, ciphertext, aad)) {
+		return nil, ErrDecryption
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrDecryption
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, ErrDecryption
+	}
+	expected := make([]byte, padding)
+	for i := range expected {
+		expected[i] = byte(padding)
+	}
+	if subtle.ConstantTimeCompare(plaintext[len(plaintext)-padding:], expected) != 1 {
+		return nil, ErrDecryption
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// cbcTag computes the authentication tag for AES CBC HMAC SHA-2, as
+// defined in RFC 7518, Section 5.2.2.1.
+func (c contentCipher) cbcTag(macKey, iv, ciphertext, aad []byte) []byte {
+	al := make([]byte, 8)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*8)
+
+	h := hmac.New(c.hash, macKey)
+	h.Write(aad)
+	h.Write(iv)
+	h.Write(ciphertext)
+	h.Write(al)
+
+	return h.Sum(nil)[:len(macKey)]
+}
+
+// newGCM returns AES GCM with the given key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,190 @@
+// Package jwe implements JSON Web Encryption (RFC 7516) using JSON Web
+// Keys.
+package jwe
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ericyan/jwk"
+)
+
+// ErrDecryption is returned when a message cannot be decrypted or fails
+// authentication.
+var ErrDecryption = errors.New("jwe: decryption failed")
+
+// Header represents the JOSE header parameters defined in RFC 7516,
+// Section 4.1 and RFC 7518, Sections 4.6.1 and 4.8.1.
+type Header struct {
+	Algorithm           string          `json:"alg"`
+	Encryption          string          `json:"enc"`
+	Compression         string          `json:"zip,omitempty"`
+	KeyID               string          `json:"kid,omitempty"`
+	Type                string          `json:"typ,omitempty"`
+	ContentType         string          `json:"cty,omitempty"`
+	Critical            []string        `json:"crit,omitempty"`
+	EphemeralPublicKey  json.RawMessage `json:"epk,omitempty"`
+	AgreementPartyUInfo string          `json:"apu,omitempty"`
+	AgreementPartyVInfo string          `json:"apv,omitempty"`
+	PBES2SaltInput      string          `json:"p2s,omitempty"`
+	PBES2Count          int             `json:"p2c,omitempty"`
+}
+
+// Message represents a JSON Web Encryption.
+type Message struct {
+	Header       *Header
+	EncryptedKey []byte
+	IV           []byte
+	Ciphertext   []byte
+	Tag          []byte
+
+	protected string // base64url-encoded protected header, as authenticated
+}
+
+// Encrypt encrypts plaintext for the recipient key and returns the JWE
+// Compact Serialization. If header does not specify a key management
+// algorithm, the algorithm of the key is used. The content encryption
+// algorithm must be specified. The key ID is included unless header
+// specifies one.
+func Encrypt(plaintext []byte, key jwk.Key, header *Header) ([]byte, error) {
+	if key == nil {
+		return nil, errors.New("jwe: missing key")
+	}
+
+	h := new(Header)
+	if header != nil {
+		*h = *header
+	}
+	if h.Algorithm == "" {
+		h.Algorithm = key.Alg()
+	}
+	if h.Algorithm == "" {
+		return nil, errors.New("jwe: missing key management algorithm")
+	}
+	if h.KeyID == "" {
+		h.KeyID = key.ID()
+	}
+	if h.Compression != "" {
+		return nil, errors.New("jwe: compression not supported")
+	}
+
+	enc, err := lookupContentCipher(h.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, encryptedKey, err := wrapKey(h, key, enc)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	protected := encode(data)
+
+	iv, err := randomBytes(enc.ivSize)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, tag, err := enc.seal(cek, iv, plaintext, []byte(protected))
+	if err != nil {
+		return nil, err
+	}
+
+	parts := []string{protected, encode(encryptedKey), encode(iv), encode(ciphertext), encode(tag)}
+	var buf bytes.Buffer
+	for i, part := range parts {
+		if i > 0 {
+			buf.WriteByte('.')
+		}
+		buf.WriteString(part)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Parse parses data in the JWE Compact Serialization. The message is not
+// decrypted.
+func Parse(data []byte) (*Message, error) {
+	parts := bytes.Split(data, []byte{'.'})
+	if len(parts) != 5 {
+		return nil, errors.New("jwe: invalid compact serialization")
+	}
+
+	decoded := make([][]byte, len(parts))
+	for i, part := range parts {
+		var err error
+		decoded[i], err = decode(string(part))
+		if err != nil {
+			return nil, fmt.Errorf("jwe: invalid compact serialization: %v", err)
+		}
+	}
+
+	h := new(Header)
+	err := json.Unmarshal(decoded[0], h)
+	if err != nil {
+		return nil, fmt.Errorf("jwe: invalid protected header: %v", err)
+	}
+
+	return &Message{
+		Header:       h,
+		EncryptedKey: decoded[1],
+		IV:           decoded[2],
+		Ciphertext:   decoded[3],
+		Tag:          decoded[4],
+		protected:    string(parts[0]),
+	}, nil
+}
+
+// Decrypt parses and decrypts data in the JWE Compact Serialization
+// using the recipient key and returns the plaintext.
+func Decrypt(data []byte, key jwk.Key) ([]byte, error) {
+	msg, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.Decrypt(key)
+}
+
+// Decrypt decrypts the message using the recipient key and returns the
+// plaintext.
+func (msg *Message) Decrypt(key jwk.Key) ([]byte, error) {
+	h := msg.Header
+	if h == nil {
+		return nil, errors.New("jwe: missing header")
+	}
+	if len(h.Critical) > 0 {
+		return nil, fmt.Errorf("jwe: unsupported critical header parameters %q", h.Critical)
+	}
+	if h.Compression != "" {
+		return nil, errors.New("jwe: compression not supported")
+	}
+
+	enc, err := lookupContentCipher(h.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := unwrapKey(h, key, enc, msg.EncryptedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return enc.open(cek, msg.IV, msg.Ciphertext, msg.Tag, []byte(msg.protected))
+}
+
+// encode returns the base64url encoding of data without padding.
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decode decodes base64url-encoded data without padding.
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.Strict().DecodeString(s)
+}
@@ -0,0 +1,240 @@
+package jwe
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"strings"
+	"testing"
+
+	"github.com/ericyan/jwk"
+)
+
+var (
+	rsaTestKey, _       = rsa.GenerateKey(rand.Reader, 2048)
+	ecdsaTestKeyP256, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecdsaTestKeyP521, _ = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+)
+
+func mustKey(t *testing.T, key jwk.CryptoKey, params *jwk.Params) jwk.Key {
+	k, err := jwk.New(key, params)
+	if err != nil {
+		t.Fatal("failed to create key:", err)
+	}
+
+	return k
+}
+
+func TestRFC7516Example(t *testing.T) {
+	// Test vector from Appendix A.3 of RFC 7516
+	key, err := jwk.Parse([]byte(`{"kty":"oct","k":"GawgguFyGrWKav7AX4VKUg"}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	data := "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0." +
+		"6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ." +
+		"AxY8DCtDaGlsbGljb3RoZQ." +
+		"KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY." +
+		"U0m_YmjN04DJvceFICbCVQ"
+
+	plaintext, err := Decrypt([]byte(data), key)
+	if err != nil {
+		t.Fatal("failed to decrypt:", err)
+	}
+	if string(plaintext) != "Live long and prosper." {
+		t.Errorf("unexpected plaintext: %s", plaintext)
+	}
+}
+
+func TestRFC7518ECDHExample(t *testing.T) {
+	// Test vector from Appendix C of RFC 7518
+	alice, _ := jwk.ParseECDSAPrivateKey([]byte(`{"kty":"EC","crv":"P-256",
+		"x":"gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",
+		"y":"SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps",
+		"d":"0_NxaRPUMQoAJt50Gz8YiTr8gRTwyEaCumd-MToTmIo"}`))
+	bob, _ := jwk.ParseECDSAPrivateKey([]byte(`{"kty":"EC","crv":"P-256",
+		"x":"weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ",
+		"y":"e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck",
+		"d":"VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"}`))
+	if alice == nil || bob == nil {
+		t.Fatal("failed to parse keys")
+	}
+
+	priv, _ := alice.CryptoKey().(*ecdsa.PrivateKey).ECDH()
+	pub, _ := bob.CryptoKey().(*ecdsa.PrivateKey).PublicKey.ECDH()
+	z, err := priv.ECDH(pub)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	h := &Header{
+		Algorithm:           ECDHES,
+		Encryption:          A128GCM,
+		AgreementPartyUInfo: "QWxpY2U",
+		AgreementPartyVInfo: "Qm9i",
+	}
+	derived, err := deriveKey(h, keyManagements[ECDHES], contentCiphers[A128GCM], z)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if encode(derived) != "VqqN6vgjbSBcIijNcacQGg" {
+		t.Errorf("unexpected derived key: %s", encode(derived))
+	}
+}
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	secret := func(n int) []byte { return bytes.Repeat([]byte{0x42}, n) }
+
+	cases := []struct {
+		alg  string
+		key  jwk.CryptoKey
+		priv jwk.CryptoKey
+	}{
+		{RSAOAEP, &rsaTestKey.PublicKey, rsaTestKey},
+		{RSAOAEP256, &rsaTestKey.PublicKey, rsaTestKey},
+		{A128KW, secret(16), secret(16)},
+		{A192KW, secret(24), secret(24)},
+		{A256KW, secret(32), secret(32)},
+		{ECDHES, &ecdsaTestKeyP256.PublicKey, ecdsaTestKeyP256},
+		{ECDHESA128KW, &ecdsaTestKeyP256.PublicKey, ecdsaTestKeyP256},
+		{ECDHESA192KW, &ecdsaTestKeyP521.PublicKey, ecdsaTestKeyP521},
+		{ECDHESA256KW, &ecdsaTestKeyP521.PublicKey, ecdsaTestKeyP521},
+	}
+	encs := []string{A128CBCHS256, A192CBCHS384, A256CBCHS512, A128GCM, A192GCM, A256GCM}
+
+	plaintext := []byte("You can trust us to stick with you through thick and thin.")
+	for _, c := range cases {
+		for _, enc := range encs {
+			key := mustKey(t, c.key, &jwk.Params{KeyID: "foo", Algorithm: c.alg})
+			priv := mustKey(t, c.priv, &jwk.Params{KeyUse: "enc"})
+
+			data, err := Encrypt(plaintext, key, &Header{Encryption: enc})
+			if err != nil {
+				t.Fatalf("%s %s: failed to encrypt: %v", c.alg, enc, err)
+			}
+
+			msg, err := Parse(data)
+			if err != nil {
+				t.Fatalf("%s %s: failed to parse: %v", c.alg, enc, err)
+			}
+			if msg.Header.Algorithm != c.alg || msg.Header.Encryption != enc || msg.Header.KeyID != "foo" {
+				t.Errorf("%s %s: unexpected header: %+v", c.alg, enc, msg.Header)
+			}
+
+			decrypted, err := msg.Decrypt(priv)
+			if err != nil {
+				t.Fatalf("%s %s: failed to decrypt: %v", c.alg, enc, err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("%s %s: plaintext mismatch", c.alg, enc)
+			}
+
+			msg.Ciphertext[0] ^= 1
+			_, err = msg.Decrypt(priv)
+			if err != ErrDecryption {
+				t.Errorf("%s %s: expected decryption error on tampered ciphertext, got %v", c.alg, enc, err)
+			}
+		}
+	}
+}
+
+func TestDirect(t *testing.T) {
+	key := mustKey(t, bytes.Repeat([]byte{0x42}, 32), &jwk.Params{Algorithm: Direct})
+
+	data, err := Encrypt([]byte("foo"), key, &Header{Encryption: A256GCM})
+	if err != nil {
+		t.Fatal("failed to encrypt:", err)
+	}
+	plaintext, err := Decrypt(data, key)
+	if err != nil {
+		t.Fatal("failed to decrypt:", err)
+	}
+	if string(plaintext) != "foo" {
+		t.Error("plaintext mismatch")
+	}
+
+	_, err = Encrypt([]byte("foo"), key, &Header{Encryption: A128GCM})
+	if err == nil {
+		t.Error("expected error on key size mismatch")
+	}
+}
+
+func TestEncryptErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		key    jwk.Key
+		header *Header
+	}{
+		{"missing algorithm", mustKey(t, &rsaTestKey.PublicKey, nil), &Header{Encryption: A128GCM}},
+		{"missing encryption", mustKey(t, &rsaTestKey.PublicKey, nil), &Header{Algorithm: RSAOAEP}},
+		{"unsupported algorithm", mustKey(t, &rsaTestKey.PublicKey, nil), &Header{Algorithm: "RSA1_5", Encryption: A128GCM}},
+		{"wrong key type", mustKey(t, &rsaTestKey.PublicKey, nil), &Header{Algorithm: A128KW, Encryption: A128GCM}},
+		{"algorithm mismatch", mustKey(t, &rsaTestKey.PublicKey, &jwk.Params{Algorithm: RSAOAEP}), &Header{Algorithm: RSAOAEP256, Encryption: A128GCM}},
+		{"signature key", mustKey(t, &rsaTestKey.PublicKey, &jwk.Params{KeyUse: "sig"}), &Header{Algorithm: RSAOAEP, Encryption: A128GCM}},
+		{"key ops", mustKey(t, &rsaTestKey.PublicKey, &jwk.Params{KeyOps: []string{"verify"}}), &Header{Algorithm: RSAOAEP, Encryption: A128GCM}},
+		{"wrong key size", mustKey(t, []byte{1, 2, 3, 4}, nil), &Header{Algorithm: A128KW, Encryption: A128GCM}},
+		{"compression", mustKey(t, &rsaTestKey.PublicKey, nil), &Header{Algorithm: RSAOAEP, Encryption: A128GCM, Compression: "DEF"}},
+	}
+
+	for _, c := range cases {
+		_, err := Encrypt([]byte("foo"), c.key, c.header)
+		if err == nil {
+			t.Errorf("expected error on encrypting with %s", c.name)
+		}
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	pub := mustKey(t, &rsaTestKey.PublicKey, nil)
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	data, err := Encrypt([]byte("foo"), pub, &Header{Algorithm: RSAOAEP, Encryption: A128GCM})
+	if err != nil {
+		t.Fatal("failed to encrypt:", err)
+	}
+
+	for _, key := range []jwk.Key{pub, mustKey(t, other, nil)} {
+		_, err = Decrypt(data, key)
+		if err == nil {
+			t.Error("expected error on decrypting with wrong key")
+		}
+	}
+
+	for _, data := range []string{"", "a.b.c.d", "a.b.c.d.e.f", "e30.a.b.c.!"} {
+		_, err := Decrypt([]byte(data), mustKey(t, rsaTestKey, nil))
+		if err == nil {
+			t.Errorf("expected error on decrypting '%s'", data)
+		}
+	}
+}
+
+func TestRSAKeySize(t *testing.T) {
+	small, _ := rsa.GenerateKey(rand.Reader, 1024)
+
+	_, err := Encrypt([]byte("foo"), mustKey(t, &small.PublicKey, nil), &Header{Algorithm: RSAOAEP, Encryption: A128GCM})
+	if err == nil {
+		t.Error("expected error on encrypting with small RSA key")
+	}
+
+	// Build a valid message for the small key by hand.
+	protected := encode([]byte(`{"alg":"RSA-OAEP","enc":"A128GCM"}`))
+	cek := make([]byte, 16)
+	iv := make([]byte, 12)
+	encryptedKey, _ := rsa.EncryptOAEP(sha1.New(), rand.Reader, &small.PublicKey, cek, nil)
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	sealed := gcm.Seal(nil, iv, []byte("foo"), []byte(protected))
+	ciphertext, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+	data := strings.Join([]string{protected, encode(encryptedKey), encode(iv), encode(ciphertext), encode(tag)}, ".")
+
+	_, err = Decrypt([]byte(data), mustKey(t, small, nil))
+	if err == nil || err == ErrDecryption {
+		t.Error("expected error on decrypting with small RSA key, got", err)
+	}
+}
@@ -0,0 +1,119 @@
+package jwe
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ericyan/jwk"
+)
+
+// Content types of encrypted JSON Web Keys and JSON Web Key Sets, as
+// described in RFC 7517, Sections 7 and 8.
+const (
+	ContentTypeKey = "jwk+json"
+	ContentTypeSet = "jwk-set+json"
+)
+
+// EncryptKey encrypts key, including its private members, with password
+// using PBES2-HS256+A128KW and A128CBC-HS256, as described in RFC 7517,
+// Section 7.
+func EncryptKey(key jwk.Key, password []byte) ([]byte, error) {
+	if key == nil {
+		return nil, errors.New("jwe: missing key")
+	}
+
+	data, err := jwk.MarshalPrivate(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptWithPassword(data, password, ContentTypeKey)
+}
+
+// DecryptKey decrypts a JSON Web Key encrypted by EncryptKey.
+func DecryptKey(data []byte, password []byte) (jwk.Key, error) {
+	plaintext, err := decryptWithPassword(data, password, ContentTypeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return jwk.Parse(plaintext)
+}
+
+// EncryptSet encrypts set, including the private members of its keys,
+// with password using PBES2-HS256+A128KW and A128CBC-HS256, as described
+// in RFC 7517, Section 8.
+func EncryptSet(set *jwk.Set, password []byte) ([]byte, error) {
+	if set == nil {
+		return nil, errors.New("jwe: missing key set")
+	}
+
+	data, err := set.MarshalPrivate()
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptWithPassword(data, password, ContentTypeSet)
+}
+
+// DecryptSet decrypts a JSON Web Key Set encrypted by EncryptSet.
+func DecryptSet(data []byte, password []byte) (*jwk.Set, error) {
+	plaintext, err := decryptWithPassword(data, password, ContentTypeSet)
+	if err != nil {
+		return nil, err
+	}
+
+	set := new(jwk.Set)
+	err = json.Unmarshal(plaintext, set)
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// encryptWithPassword encrypts plaintext of content type cty with
+// password.
+func encryptWithPassword(plaintext, password []byte, cty string) ([]byte, error) {
+	key, err := passwordKey(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return Encrypt(plaintext, key, &Header{
+		Algorithm:   PBES2HS256A128KW,
+		Encryption:  A128CBCHS256,
+		ContentType: cty,
+	})
+}
+
+// decryptWithPassword decrypts data with password, checking that it has
+// content type cty.
+func decryptWithPassword(data, password []byte, cty string) ([]byte, error) {
+	key, err := passwordKey(password)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Header.Algorithm != PBES2HS256A128KW {
+		return nil, errors.New("jwe: unexpected key management algorithm")
+	}
+	if msg.Header.ContentType != cty {
+		return nil, errors.New("jwe: unexpected content type")
+	}
+
+	return msg.Decrypt(key)
+}
+
+// passwordKey returns a symmetric key holding password.
+func passwordKey(password []byte) (jwk.Key, error) {
+	if len(password) == 0 {
+		return nil, errors.New("jwe: missing password")
+	}
+
+	return jwk.NewOctetSequenceKey(password, &jwk.Params{Algorithm: PBES2HS256A128KW})
+}
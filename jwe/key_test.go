@@ -0,0 +1,116 @@
+package jwe
+
+import (
+	"testing"
+
+	"github.com/ericyan/jwk"
+)
+
+func TestEncryptKey(t *testing.T) {
+	key := mustKey(t, rsaTestKey, &jwk.Params{KeyID: "foo"})
+	password := []byte("Thus from my lips, by yours, my sin is purged.")
+
+	data, err := EncryptKey(key, password)
+	if err != nil {
+		t.Fatal("failed to encrypt key:", err)
+	}
+
+	msg, err := Parse(data)
+	if err != nil {
+		t.Fatal("failed to parse:", err)
+	}
+	h := msg.Header
+	if h.Algorithm != PBES2HS256A128KW || h.Encryption != A128CBCHS256 || h.ContentType != ContentTypeKey {
+		t.Errorf("unexpected header: %+v", h)
+	}
+	if h.PBES2Count != DefaultPBES2Count || h.PBES2SaltInput == "" {
+		t.Errorf("unexpected PBES2 parameters: %+v", h)
+	}
+
+	decrypted, err := DecryptKey(data, password)
+	if err != nil {
+		t.Fatal("failed to decrypt key:", err)
+	}
+	if !jwk.EqualWithParams(key, decrypted) {
+		t.Error("round-trip of encrypted key gave different keys")
+	}
+
+	_, err = DecryptKey(data, []byte("wrong password"))
+	if err == nil {
+		t.Error("expected error on wrong password")
+	}
+
+	_, err = DecryptSet(data, password)
+	if err == nil {
+		t.Error("expected error on decrypting key as set")
+	}
+
+	_, err = EncryptKey(key, nil)
+	if err == nil {
+		t.Error("expected error on empty password")
+	}
+}
+
+func TestEncryptSet(t *testing.T) {
+	set := &jwk.Set{Keys: []jwk.Key{
+		mustKey(t, ecdsaTestKeyP256, &jwk.Params{KeyID: "ec"}),
+		mustKey(t, []byte{1, 2, 3, 4}, &jwk.Params{KeyID: "oct"}),
+	}}
+	password := []byte("correct horse battery staple")
+
+	data, err := EncryptSet(set, password)
+	if err != nil {
+		t.Fatal("failed to encrypt set:", err)
+	}
+
+	decrypted, err := DecryptSet(data, password)
+	if err != nil {
+		t.Fatal("failed to decrypt set:", err)
+	}
+	if len(decrypted.Keys) != len(set.Keys) {
+		t.Fatal("set length mismatch")
+	}
+	for i := range set.Keys {
+		if !jwk.EqualWithParams(set.Keys[i], decrypted.Keys[i]) {
+			t.Errorf("key %d mismatch", i)
+		}
+	}
+}
+
+func TestPBES2(t *testing.T) {
+	for _, alg := range []string{PBES2HS256A128KW, PBES2HS384A192KW, PBES2HS512A256KW} {
+		key := mustKey(t, []byte("password"), &jwk.Params{Algorithm: alg})
+		data, err := Encrypt([]byte("foo"), key, &Header{Algorithm: alg, Encryption: A128GCM, PBES2Count: minPBES2Count})
+		if err != nil {
+			t.Fatalf("%s: failed to encrypt: %v", alg, err)
+		}
+
+		plaintext, err := Decrypt(data, key)
+		if err != nil {
+			t.Fatalf("%s: failed to decrypt: %v", alg, err)
+		}
+		if string(plaintext) != "foo" {
+			t.Errorf("%s: plaintext mismatch", alg)
+		}
+	}
+
+	key := mustKey(t, []byte("password"), &jwk.Params{Algorithm: PBES2HS256A128KW})
+	_, err := Encrypt([]byte("foo"), key, &Header{Algorithm: PBES2HS256A128KW, Encryption: A128GCM, PBES2Count: maxPBES2Count + 1})
+	if err == nil {
+		t.Error("expected error on excessive p2c")
+	}
+
+	// PBES2 must not be accepted for symmetric keys not restricted to it.
+	data, err := Encrypt([]byte("foo"), key, &Header{Algorithm: PBES2HS256A128KW, Encryption: A128GCM, PBES2Count: minPBES2Count})
+	if err != nil {
+		t.Fatal("failed to encrypt:", err)
+	}
+	_, err = Decrypt(data, mustKey(t, []byte("password"), nil))
+	if err == nil {
+		t.Error("expected error on decrypting PBES2 with unrestricted key")
+	}
+	_, err = Encrypt([]byte("foo"), mustKey(t, []byte("password"), nil), &Header{Algorithm: PBES2HS256A128KW, Encryption: A128GCM})
+	if err == nil {
+		t.Error("expected error on encrypting PBES2 with unrestricted key")
+	}
+}
@@ -0,0 +1,58 @@
+package jwe
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestKeyWrap(t *testing.T) {
+	// Test vectors from Section 4 of RFC 3394
+	cases := []struct {
+		kek, key, wrapped string
+	}{
+		{
+			"000102030405060708090A0B0C0D0E0F",
+			"00112233445566778899AABBCCDDEEFF",
+			"1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5",
+		},
+		{
+			"000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			"00112233445566778899AABBCCDDEEFF",
+			"64E8C3F9CE0F5BA263E9777905818A2A93C8191E7D6E8AE7",
+		},
+		{
+			"000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			"00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F",
+			"28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21",
+		},
+	}
+
+	for i, c := range cases {
+		kek, _ := hex.DecodeString(c.kek)
+		key, _ := hex.DecodeString(c.key)
+		expected, _ := hex.DecodeString(c.wrapped)
+
+		wrapped, err := keyWrap(kek, key)
+		if err != nil {
+			t.Fatalf("case %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(wrapped, expected) {
+			t.Errorf("case %d: unexpected wrapped key: %X", i, wrapped)
+		}
+
+		unwrapped, err := keyUnwrap(kek, wrapped)
+		if err != nil {
+			t.Fatalf("case %d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(unwrapped, key) {
+			t.Errorf("case %d: unexpected unwrapped key: %X", i, unwrapped)
+		}
+
+		wrapped[0] ^= 1
+		_, err = keyUnwrap(kek, wrapped)
+		if err == nil {
+			t.Errorf("case %d: expected error on corrupted wrapped key", i)
+		}
+	}
+}
@@ -1,20 +1,20 @@
 package jwk
 
 import (
+	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"encoding/json"
-	"errors"
 	"fmt"
-
-	"github.com/ericyan/jwk/internal/base64url"
 )
 
-// JSON Web Key types defined in RFC7518, Section 6.
+// JSON Web Key types defined in RFC7518, Section 6 and RFC 8037, Section 2.
 const (
 	TypeEC  = "EC"
 	TypeRSA = "RSA"
 	TypeOCT = "oct"
+	TypeOKP = "OKP"
 )
 
 // CryptoKey represents a cryptographic key using an unspecified algorithm.
@@ -27,6 +27,10 @@ type Params struct {
 	KeyOps    []string `json:"key_ops,omitempty"`
 	Algorithm string   `json:"alg,omitempty"`
 	KeyID     string   `json:"kid,omitempty"`
+
+	// X509SHA256Thumbprint is the base64url-encoded SHA-256 thumbprint
+	// of the DER encoding of the X.509 certificate of the key.
+	X509SHA256Thumbprint string `json:"x5t#S256,omitempty"`
 }
 
 // ID returns the key ID parameter.
@@ -34,13 +38,70 @@ func (p *Params) ID() string {
 	return p.KeyID
 }
 
+// Type returns the key type parameter.
+func (p *Params) Type() string {
+	return p.KeyType
+}
+
+// Alg returns the algorithm parameter.
+func (p *Params) Alg() string {
+	return p.Algorithm
+}
+
+// Parameters returns the common parameters.
+func (p *Params) Parameters() *Params {
+	return p
+}
+
+// publicKeyOps maps private key operations defined in RFC 7517, Section
+// 4.3 to their public counterparts.
+var publicKeyOps = map[string]string{
+	"sign":      "verify",
+	"decrypt":   "encrypt",
+	"unwrapKey": "wrapKey",
+}
+
+// public returns a copy of the params suitable for the public counterpart
+// of a private key, with private key operations remapped.
+func (p *Params) public() *Params {
+	params := *p
+	if p.KeyOps == nil {
+		return &params
+	}
+
+	params.KeyOps = make([]string, 0, len(p.KeyOps))
+	seen := make(map[string]bool)
+	for _, op := range p.KeyOps {
+		if pubOp, ok := publicKeyOps[op]; ok {
+			op = pubOp
+		}
+		if !seen[op] {
+			params.KeyOps = append(params.KeyOps, op)
+			seen[op] = true
+		}
+	}
+
+	return &params
+}
+
 // Key represents a JSON Web Key.
+//
+// The common parameters of a key are available through ID, Type, Alg and
+// Parameters. IsPrivate reports whether the key contains private or
+// secret key material, which is the case for private keys as well as
+// symmetric keys.
 type Key interface {
 	ID() string
+	Type() string
+	Alg() string
+	Parameters() *Params
+	IsPrivate() bool
 	CryptoKey() CryptoKey
 }
 
-// New creates a new Key.
+// New creates a new Key. A crypto.Signer of a type not otherwise
+// supported, such as one backed by an external key store, is wrapped as a
+// SignerKey.
 func New(key CryptoKey, params *Params) (Key, error) {
 	switch k := key.(type) {
 	case *ecdsa.PublicKey:
@@ -51,10 +112,16 @@ func New(key CryptoKey, params *Params) (Key, error) {
 		return NewRSAPublicKey(k, params)
 	case *rsa.PrivateKey:
 		return NewRSAPrivateKey(k, params)
+	case ed25519.PublicKey:
+		return NewEd25519PublicKey(k, params)
+	case ed25519.PrivateKey:
+		return NewEd25519PrivateKey(k, params)
 	case []byte:
 		return NewOctetSequenceKey(k, params)
+	case crypto.Signer:
+		return NewSignerKey(k, params)
 	default:
-		return nil, errors.New("jwk: unsupported crypto key")
+		return nil, ErrUnsupportedKeyType
 	}
 }
 
@@ -62,11 +129,11 @@ func New(key CryptoKey, params *Params) (Key, error) {
 func Parse(data []byte) (Key, error) {
 	var hints struct {
 		KeyType string           `json:"kty"`
-		D       *base64url.Value `json:"d,omitempty"`
+		D       *json.RawMessage `json:"d,omitempty"`
 	}
 	err := json.Unmarshal(data, &hints)
 	if err != nil {
-		return nil, err
+		return nil, unmarshalError(err)
 	}
 
 	switch hints.KeyType {
@@ -84,8 +151,16 @@ func Parse(data []byte) (Key, error) {
 		return ParseRSAPublicKey(data)
 	case TypeOCT:
 		return ParseOctetSequenceKey(data)
+	case TypeOKP:
+		if hints.D != nil {
+			return ParseEd25519PrivateKey(data)
+		}
+
+		return ParseEd25519PublicKey(data)
+	case "":
+		return nil, parseError("kty", ErrMissingMember)
 	default:
-		return nil, fmt.Errorf("jwk: unsupported key type '%s'", hints.KeyType)
+		return nil, parseError("kty", fmt.Errorf("%w %q", ErrUnsupportedKeyType, hints.KeyType))
 	}
 }
 
@@ -101,14 +176,14 @@ func (s *Set) UnmarshalJSON(data []byte) error {
 	}
 	err := json.Unmarshal(data, &raw)
 	if err != nil {
-		return err
+		return unmarshalError(err)
 	}
 
 	keys := make([]Key, len(raw.Keys))
 	for i, jwk := range raw.Keys {
 		key, err := Parse(jwk)
 		if err != nil {
-			return err
+			return inSet(err, i)
 		}
 
 		keys[i] = key
@@ -117,3 +192,22 @@ func (s *Set) UnmarshalJSON(data []byte) error {
 	s.Keys = keys
 	return nil
 }
+
+// Public returns a new Set containing the public keys in s. Private keys
+// are replaced by their public counterparts and symmetric keys are
+// omitted.
+func (s *Set) Public() *Set {
+	pub := &Set{Keys: []Key{}}
+	for _, key := range s.Keys {
+		if !key.IsPrivate() {
+			pub.Keys = append(pub.Keys, key)
+			continue
+		}
+
+		if k, ok := key.(interface{ PublicKey() Key }); ok {
+			pub.Keys = append(pub.Keys, k.PublicKey())
+		}
+	}
+
+	return pub
+}
@@ -6,16 +6,6 @@ import (
 	"testing"
 )
 
-func equalParams(a, b *Params) bool {
-	if a.KeyType != b.KeyType && a.KeyUse != b.KeyUse && a.Algorithm != a.Algorithm && a.KeyID == b.KeyID {
-		return false
-	}
-
-	// TODO: Check KeyOps
-
-	return true
-}
-
 func TestRFC7517ExampleSets(t *testing.T) {
 	// Test vectors from Appendix A of RFC 7517
 	cases := []struct {
@@ -104,30 +94,39 @@ func TestRFC7517ExampleSets(t *testing.T) {
 			t.Fatalf("set length mismatch")
 		}
 		for i, params := range c.params {
-			switch key := jwks.Keys[i].(type) {
-			case *ECDSAPublicKey:
-				if !equalParams(key.Params, &params) {
-					t.Fatalf("params mismatch")
-				}
-			case *ECDSAPrivateKey:
-				if !equalParams(key.Params, &params) {
-					t.Fatalf("params mismatch")
-				}
-			case *RSAPublicKey:
-				if !equalParams(key.Params, &params) {
-					t.Fatalf("params mismatch")
-				}
-			case *RSAPrivateKey:
-				if !equalParams(key.Params, &params) {
-					t.Fatalf("params mismatch")
-				}
-			case *OctetSequenceKey:
-				if !equalParams(key.Params, &params) {
-					t.Fatalf("params mismatch")
-				}
-			default:
-				t.Error("unsupported key type:", reflect.TypeOf(jwks.Keys[i]))
+			key := jwks.Keys[i]
+			if !equalParams(key.Parameters(), &params) {
+				t.Fatalf("params mismatch")
+			}
+			if key.Type() != params.KeyType || key.ID() != params.KeyID || key.Alg() != params.Algorithm {
+				t.Fatalf("params accessors mismatch")
+			}
+
+			_, hasPublic := key.(interface{ PublicKey() Key })
+			if key.IsPrivate() != (hasPublic || key.Type() == TypeOCT) {
+				t.Error("unexpected IsPrivate for key:", reflect.TypeOf(key))
 			}
 		}
 	}
 }
+
+func TestSetPublic(t *testing.T) {
+	ecKey, _ := NewECDSAPrivateKey(ecdsaTestKeyP256, nil)
+	rsaKey, _ := NewRSAPublicKey(&rsaTestKey.PublicKey, nil)
+	octKey, _ := NewOctetSequenceKey([]byte{1, 2, 3, 4}, nil)
+	set := &Set{Keys: []Key{ecKey, rsaKey, octKey}}
+
+	pub := set.Public()
+	if len(pub.Keys) != 2 {
+		t.Fatal("unexpected number of public keys:", len(pub.Keys))
+	}
+	if _, ok := pub.Keys[0].(*ECDSAPublicKey); !ok {
+		t.Error("private key not replaced by public key:", reflect.TypeOf(pub.Keys[0]))
+	}
+	if pub.Keys[1] != rsaKey {
+		t.Error("public key not preserved")
+	}
+	if len(set.Keys) != 3 {
+		t.Error("original set modified")
+	}
+}
@@ -0,0 +1,248 @@
+package jws
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256" // register SHA-256 for crypto.Hash
+	_ "crypto/sha512" // register SHA-384 and SHA-512 for crypto.Hash
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ericyan/jwk"
+)
+
+// Digital signature and MAC algorithms defined in RFC 7518, Section 3.1.
+const (
+	HS256 = "HS256"
+	HS384 = "HS384"
+	HS512 = "HS512"
+	RS256 = "RS256"
+	RS384 = "RS384"
+	RS512 = "RS512"
+	ES256 = "ES256"
+	ES384 = "ES384"
+	ES512 = "ES512"
+	PS256 = "PS256"
+	PS384 = "PS384"
+	PS512 = "PS512"
+)
+
+// minRSAKeySize is the minimum RSA key size in bits required by RFC 7518,
+// Sections 3.3 and 3.5.
+const minRSAKeySize = 2048
+
+// algorithm describes a digital signature or MAC algorithm.
+type algorithm struct {
+	hash  crypto.Hash
+	kty   string
+	pss   bool
+	curve elliptic.Curve
+}
+
+var algorithms = map[string]algorithm{
+	HS256: {hash: crypto.SHA256, kty: jwk.TypeOCT},
+	HS384: {hash: crypto.SHA384, kty: jwk.TypeOCT},
+	HS512: {hash: crypto.SHA512, kty: jwk.TypeOCT},
+	RS256: {hash: crypto.SHA256, kty: jwk.TypeRSA},
+	RS384: {hash: crypto.SHA384, kty: jwk.TypeRSA},
+	RS512: {hash: crypto.SHA512, kty: jwk.TypeRSA},
+	PS256: {hash: crypto.SHA256, kty: jwk.TypeRSA, pss: true},
+	PS384: {hash: crypto.SHA384, kty: jwk.TypeRSA, pss: true},
+	PS512: {hash: crypto.SHA512, kty: jwk.TypeRSA, pss: true},
+	ES256: {hash: crypto.SHA256, kty: jwk.TypeEC, curve: elliptic.P256()},
+	ES384: {hash: crypto.SHA384, kty: jwk.TypeEC, curve: elliptic.P384()},
+	ES512: {hash: crypto.SHA512, kty: jwk.TypeEC, curve: elliptic.P521()},
+}
+
+// lookup returns the algorithm named alg after checking that key is
+// allowed to perform op with it.
+func lookup(alg string, key jwk.Key, op string) (algorithm, error) {
+	a, ok := algorithms[alg]
+	if !ok {
+		return a, fmt.Errorf("jws: unsupported algorithm '%s'", alg)
+	}
+	if key == nil {
+		return a, errors.New("jws: missing key")
+	}
+	if key.Type() != a.kty {
+		return a, fmt.Errorf("jws: key type '%s' cannot be used with algorithm '%s'", key.Type(), alg)
+	}
+	if key.Alg() != "" && key.Alg() != alg {
+		return a, fmt.Errorf("jws: key is restricted to algorithm '%s'", key.Alg())
+	}
+
+	params := key.Parameters()
+	if params.KeyUse != "" && params.KeyUse != "sig" {
+		return a, fmt.Errorf("jws: key is intended for use '%s'", params.KeyUse)
+	}
+	if len(params.KeyOps) > 0 && !contains(params.KeyOps, op) {
+		return a, fmt.Errorf("jws: key does not permit operation '%s'", op)
+	}
+
+	return a, nil
+}
+
+// sign computes the signature or MAC of input using key.
+func sign(alg string, key jwk.Key, input []byte) ([]byte, error) {
+	a, err := lookup(alg, key, "sign")
+	if err != nil {
+		return nil, err
+	}
+
+	if a.kty == jwk.TypeOCT {
+		return mac(a, key, input)
+	}
+
+	signer, ok := key.CryptoKey().(crypto.Signer)
+	if !ok {
+		return nil, errors.New("jws: key cannot be used for signing")
+	}
+
+	h := a.hash.New()
+	h.Write(input)
+	digest := h.Sum(nil)
+
+	switch pub := signer.Public().(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeySize {
+			return nil, errors.New("jws: RSA key too small")
+		}
+
+		var opts crypto.SignerOpts = a.hash
+		if a.pss {
+			opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: a.hash}
+		}
+
+		return signer.Sign(rand.Reader, digest, opts)
+	case *ecdsa.PublicKey:
+		if pub.Curve != a.curve {
+			return nil, fmt.Errorf("jws: wrong elliptic curve for algorithm '%s'", alg)
+		}
+
+		der, err := signer.Sign(rand.Reader, digest, a.hash)
+		if err != nil {
+			return nil, err
+		}
+
+		var rs struct{ R, S *big.Int }
+		_, err = asn1.Unmarshal(der, &rs)
+		if err != nil {
+			return nil, err
+		}
+
+		size := curveSize(pub.Curve)
+		sig := make([]byte, 2*size)
+		rs.R.FillBytes(sig[:size])
+		rs.S.FillBytes(sig[size:])
+
+		return sig, nil
+	default:
+		return nil, errors.New("jws: unsupported crypto key")
+	}
+}
+
+// verify checks that sig is a valid signature or MAC of input using key.
+func verify(alg string, key jwk.Key, input, sig []byte) error {
+	a, err := lookup(alg, key, "verify")
+	if err != nil {
+		return err
+	}
+
+	if a.kty == jwk.TypeOCT {
+		expected, err := mac(a, key, input)
+		if err != nil {
+			return err
+		}
+		if !hmac.Equal(sig, expected) {
+			return ErrInvalidSignature
+		}
+
+		return nil
+	}
+
+	h := a.hash.New()
+	h.Write(input)
+	digest := h.Sum(nil)
+
+	pub := key.CryptoKey()
+	if signer, ok := pub.(crypto.Signer); ok {
+		pub = signer.Public()
+	}
+
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeySize {
+			return errors.New("jws: RSA key too small")
+		}
+
+		if a.pss {
+			err = rsa.VerifyPSS(pub, a.hash, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			err = rsa.VerifyPKCS1v15(pub, a.hash, digest, sig)
+		}
+		if err != nil {
+			return ErrInvalidSignature
+		}
+
+		return nil
+	case *ecdsa.PublicKey:
+		if pub.Curve != a.curve {
+			return fmt.Errorf("jws: wrong elliptic curve for algorithm '%s'", alg)
+		}
+
+		size := curveSize(pub.Curve)
+		if len(sig) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return ErrInvalidSignature
+		}
+
+		return nil
+	default:
+		return errors.New("jws: unsupported crypto key")
+	}
+}
+
+// mac computes the HMAC of input using the symmetric key.
+func mac(a algorithm, key jwk.Key, input []byte) ([]byte, error) {
+	secret, ok := key.CryptoKey().([]byte)
+	if !ok {
+		return nil, errors.New("jws: unsupported crypto key")
+	}
+
+	// RFC 7518, Section 3.2: the key must be at least the size of the
+	// hash output.
+	if len(secret) < a.hash.Size() {
+		return nil, errors.New("jws: HMAC key too small")
+	}
+
+	h := hmac.New(a.hash.New, secret)
+	h.Write(input)
+
+	return h.Sum(nil), nil
+}
+
+// curveSize returns the size in octets of the field elements of curve.
+func curveSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
+
+// contains reports whether s is in list.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
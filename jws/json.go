@@ -0,0 +1,151 @@
+package jws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ericyan/jwk"
+)
+
+// Policy determines which signatures of a message must be valid for the
+// message to be accepted.
+type Policy int
+
+// Verification policies.
+const (
+	// Any accepts a message if at least one signature is valid.
+	Any Policy = iota
+	// All accepts a message only if all signatures are valid.
+	All
+)
+
+// rawSignature is the JSON representation of a signature, as defined in
+// RFC 7515, Section 7.2.1.
+type rawSignature struct {
+	Protected string  `json:"protected,omitempty"`
+	Header    *Header `json:"header,omitempty"`
+	Signature string  `json:"signature"`
+}
+
+// generalMessage is the JSON representation of a message in the general
+// serialization, as defined in RFC 7515, Section 7.2.1.
+type generalMessage struct {
+	Payload    string         `json:"payload"`
+	Signatures []rawSignature `json:"signatures"`
+}
+
+// flattenedMessage is the JSON representation of a message in the
+// flattened serialization, as defined in RFC 7515, Section 7.2.2.
+type flattenedMessage struct {
+	Payload string `json:"payload"`
+	rawSignature
+}
+
+// rawMessage is used to parse a message in either the general or the
+// flattened serialization.
+type rawMessage struct {
+	Payload    *string         `json:"payload"`
+	Signatures *[]rawSignature `json:"signatures,omitempty"`
+	rawSignature
+}
+
+// SignJSON signs payload with each of keys and returns the JWS General
+// JSON Serialization. The algorithm and ID of each key is used in its
+// protected header.
+func SignJSON(payload []byte, keys ...jwk.Key) ([]byte, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("jws: missing key")
+	}
+
+	encodedPayload := encode(payload)
+	sigs := make([]rawSignature, len(keys))
+	for i, key := range keys {
+		sig, err := newSignature(encodedPayload, key, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		sigs[i] = rawSignature{
+			Protected: sig.protected,
+			Signature: encode(sig.Signature),
+		}
+	}
+
+	return json.Marshal(generalMessage{
+		Payload:    encodedPayload,
+		Signatures: sigs,
+	})
+}
+
+// SignFlattened signs payload using key and returns the JWS Flattened JSON
+// Serialization. The header is used as the protected header, as in Sign.
+func SignFlattened(payload []byte, key jwk.Key, header *Header) ([]byte, error) {
+	encodedPayload := encode(payload)
+	sig, err := newSignature(encodedPayload, key, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(flattenedMessage{
+		Payload: encodedPayload,
+		rawSignature: rawSignature{
+			Protected: sig.protected,
+			Signature: encode(sig.Signature),
+		},
+	})
+}
+
+// ParseJSON parses data in either the JWS General JSON Serialization or
+// the JWS Flattened JSON Serialization. The signatures are not verified.
+func ParseJSON(data []byte) (*Message, error) {
+	var raw rawMessage
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if raw.Payload == nil {
+		return nil, errors.New("jws: invalid JSON serialization, missing payload")
+	}
+	payload, err := decode(*raw.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("jws: invalid payload: %v", err)
+	}
+
+	var rawSigs []rawSignature
+	if raw.Signatures != nil {
+		if raw.Protected != "" || raw.Header != nil || raw.Signature != "" {
+			return nil, errors.New("jws: invalid JSON serialization, mixed general and flattened syntax")
+		}
+		rawSigs = *raw.Signatures
+	} else {
+		rawSigs = []rawSignature{raw.rawSignature}
+	}
+	if len(rawSigs) == 0 {
+		return nil, errors.New("jws: invalid JSON serialization, missing signatures")
+	}
+
+	msg := &Message{
+		Payload:    payload,
+		Signatures: make([]*Signature, len(rawSigs)),
+	}
+	for i, rawSig := range rawSigs {
+		sig, err := parseSignature(rawSig.Protected, rawSig.Signature)
+		if err != nil {
+			return nil, err
+		}
+		sig.Header = rawSig.Header
+
+		msg.Signatures[i] = sig
+	}
+
+	return msg, nil
+}
+
+// VerifyJSON verifies data in either JSON serialization against the keys
+// in set according to policy and returns the payload. Keys are resolved
+// as described in Verifier.
+func VerifyJSON(data []byte, set *jwk.Set, policy Policy) ([]byte, error) {
+	return (&Verifier{Set: set}).VerifyJSON(data, policy)
+}
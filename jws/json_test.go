@@ -0,0 +1,152 @@
+package jws
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ericyan/jwk"
+)
+
+// memberNames returns the sorted names of the top-level members in data.
+func memberNames(t *testing.T, data []byte) []string {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		t.Fatal("failed to unmarshal:", err)
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func TestSignJSON(t *testing.T) {
+	oldKey := mustKey(t, rsaTestKey, &jwk.Params{KeyID: "old", Algorithm: RS256})
+	newKey := mustKey(t, ecdsaTestKeyP256, &jwk.Params{KeyID: "new", Algorithm: ES256})
+	oldPub := mustKey(t, &rsaTestKey.PublicKey, &jwk.Params{KeyID: "old"})
+	newPub := mustKey(t, &ecdsaTestKeyP256.PublicKey, &jwk.Params{KeyID: "new"})
+
+	payload := []byte(`{"config":true}`)
+	data, err := SignJSON(payload, oldKey, newKey)
+	if err != nil {
+		t.Fatal("failed to sign:", err)
+	}
+
+	if names := memberNames(t, data); !reflect.DeepEqual(names, []string{"payload", "signatures"}) {
+		t.Error("unexpected members:", names)
+	}
+
+	msg, err := ParseJSON(data)
+	if err != nil {
+		t.Fatal("failed to parse:", err)
+	}
+	if len(msg.Signatures) != 2 || msg.Signatures[0].Protected.KeyID != "old" || msg.Signatures[1].Protected.KeyID != "new" {
+		t.Error("unexpected signatures")
+	}
+
+	cases := []struct {
+		keys   []jwk.Key
+		policy Policy
+		valid  bool
+	}{
+		{[]jwk.Key{oldPub, newPub}, All, true},
+		{[]jwk.Key{oldPub, newPub}, Any, true},
+		{[]jwk.Key{newPub}, Any, true},
+		{[]jwk.Key{newPub}, All, false},
+		{[]jwk.Key{}, Any, false},
+	}
+
+	for i, c := range cases {
+		verified, err := VerifyJSON(data, &jwk.Set{Keys: c.keys}, c.policy)
+		if c.valid && err != nil {
+			t.Errorf("case %d: failed to verify: %v", i, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+		if c.valid && !bytes.Equal(verified, payload) {
+			t.Errorf("case %d: payload mismatch", i)
+		}
+	}
+}
+
+func TestSignFlattened(t *testing.T) {
+	key := mustKey(t, hmacTestKey, &jwk.Params{KeyID: "foo", Algorithm: HS256})
+	set := &jwk.Set{Keys: []jwk.Key{key}}
+
+	data, err := SignFlattened([]byte("foo"), key, &Header{Type: "example"})
+	if err != nil {
+		t.Fatal("failed to sign:", err)
+	}
+
+	if names := memberNames(t, data); !reflect.DeepEqual(names, []string{"payload", "protected", "signature"}) {
+		t.Error("unexpected members:", names)
+	}
+
+	payload, err := VerifyJSON(data, set, All)
+	if err != nil {
+		t.Fatal("failed to verify:", err)
+	}
+	if string(payload) != "foo" {
+		t.Error("payload mismatch")
+	}
+
+	// Moving the protected header parameters to the unprotected header
+	// must invalidate the signature.
+	compact, _ := Sign([]byte("foo"), key, nil)
+	parts := bytes.Split(compact, []byte{'.'})
+	sig, _ := json.Marshal(map[string]interface{}{
+		"payload":   string(parts[1]),
+		"header":    map[string]string{"alg": HS256, "kid": "foo"},
+		"signature": string(parts[2]),
+	})
+	_, err = VerifyJSON(sig, set, All)
+	if err == nil {
+		t.Error("expected error on signature over a different protected header")
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	cases := []string{
+		`{}`,
+		`{"payload":"Zm9v"}`,
+		`{"payload":"Zm9v","signatures":[]}`,
+		`{"payload":"Zm9v","signatures":[{"signature":"Zm9v"}],"signature":"Zm9v"}`,
+		`{"payload":"!","signature":"Zm9v"}`,
+		`{"payload":"Zm9v","protected":"e30","header":{"crit":["exp"]},"signature":"Zm9v"}`,
+	}
+
+	key := mustKey(t, hmacTestKey, &jwk.Params{Algorithm: HS256})
+	for _, data := range cases {
+		_, err := VerifyJSON([]byte(data), &jwk.Set{Keys: []jwk.Key{key}}, Any)
+		if err == nil {
+			t.Errorf("expected error on verifying '%s'", data)
+		}
+	}
+}
+
+func TestHeaderUnion(t *testing.T) {
+	sig := &Signature{
+		Protected: &Header{Algorithm: HS256},
+		Header:    &Header{KeyID: "foo"},
+	}
+	h, err := sig.header()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if h.Algorithm != HS256 || h.KeyID != "foo" {
+		t.Error("unexpected header union:", h)
+	}
+
+	sig.Header.Algorithm = HS384
+	_, err = sig.header()
+	if err == nil {
+		t.Error("expected error on duplicate header parameter")
+	}
+}
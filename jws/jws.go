@@ -0,0 +1,232 @@
+// Package jws implements JSON Web Signature (RFC 7515) using JSON Web Keys.
+package jws
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ericyan/jwk"
+)
+
+// ErrInvalidSignature is returned when a signature fails verification.
+var ErrInvalidSignature = errors.New("jws: invalid signature")
+
+// Header represents the JOSE header parameters defined in RFC 7515,
+// Section 4.1.
+type Header struct {
+	Algorithm            string          `json:"alg,omitempty"`
+	JWK                  json.RawMessage `json:"jwk,omitempty"`
+	KeyID                string          `json:"kid,omitempty"`
+	X509SHA256Thumbprint string          `json:"x5t#S256,omitempty"`
+	Type                 string          `json:"typ,omitempty"`
+	ContentType          string          `json:"cty,omitempty"`
+	Critical             []string        `json:"crit,omitempty"`
+}
+
+// Signature represents a digital signature or MAC over the payload of a
+// JWS, together with its protected and unprotected header.
+type Signature struct {
+	Protected *Header
+	Header    *Header
+	Signature []byte
+
+	protected string // base64url-encoded protected header, as signed
+}
+
+// Message represents a JSON Web Signature.
+type Message struct {
+	Payload    []byte
+	Signatures []*Signature
+}
+
+// Sign signs payload using key and returns the JWS Compact Serialization.
+// If header is nil or does not specify an algorithm, the algorithm of
+// the key is used. The key ID is included unless header specifies one.
+func Sign(payload []byte, key jwk.Key, header *Header) ([]byte, error) {
+	encodedPayload := encode(payload)
+	sig, err := newSignature(encodedPayload, key, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(sig.protected + "." + encodedPayload + "." + encode(sig.Signature)), nil
+}
+
+// Parse parses data in the JWS Compact Serialization. The signature is
+// not verified.
+func Parse(data []byte) (*Message, error) {
+	parts := bytes.Split(data, []byte{'.'})
+	if len(parts) != 3 {
+		return nil, errors.New("jws: invalid compact serialization")
+	}
+
+	sig, err := parseSignature(string(parts[0]), string(parts[2]))
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := decode(string(parts[1]))
+	if err != nil {
+		return nil, fmt.Errorf("jws: invalid payload: %v", err)
+	}
+
+	return &Message{
+		Payload:    payload,
+		Signatures: []*Signature{sig},
+	}, nil
+}
+
+// Verify verifies data in the JWS Compact Serialization using key and
+// returns the payload.
+func Verify(data []byte, key jwk.Key) ([]byte, error) {
+	msg, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = msg.verify(msg.Signatures[0], key)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.Payload, nil
+}
+
+// newSignature signs the encoded payload using key.
+func newSignature(encodedPayload string, key jwk.Key, header *Header) (*Signature, error) {
+	if key == nil {
+		return nil, errors.New("jws: missing key")
+	}
+
+	h := new(Header)
+	if header != nil {
+		*h = *header
+	}
+	if h.Algorithm == "" {
+		h.Algorithm = key.Alg()
+	}
+	if h.Algorithm == "" {
+		return nil, errors.New("jws: missing algorithm")
+	}
+	if h.KeyID == "" {
+		h.KeyID = key.ID()
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	protected := encode(data)
+
+	sig, err := sign(h.Algorithm, key, []byte(protected+"."+encodedPayload))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signature{
+		Protected: h,
+		Signature: sig,
+		protected: protected,
+	}, nil
+}
+
+// parseSignature decodes the encoded protected header and signature.
+func parseSignature(protected, signature string) (*Signature, error) {
+	data, err := decode(protected)
+	if err != nil {
+		return nil, fmt.Errorf("jws: invalid protected header: %v", err)
+	}
+
+	h := new(Header)
+	if len(data) > 0 {
+		err = json.Unmarshal(data, h)
+		if err != nil {
+			return nil, fmt.Errorf("jws: invalid protected header: %v", err)
+		}
+	}
+
+	sig, err := decode(signature)
+	if err != nil {
+		return nil, fmt.Errorf("jws: invalid signature encoding: %v", err)
+	}
+
+	return &Signature{
+		Protected: h,
+		Signature: sig,
+		protected: protected,
+	}, nil
+}
+
+// header returns the union of the protected and unprotected header, as
+// described in RFC 7515, Section 7.2.1.
+func (sig *Signature) header() (*Header, error) {
+	h := new(Header)
+	if sig.Protected != nil {
+		*h = *sig.Protected
+	}
+	if sig.Header == nil {
+		return h, nil
+	}
+
+	u := sig.Header
+	if len(u.Critical) > 0 {
+		return nil, errors.New("jws: crit must be integrity protected")
+	}
+	for _, p := range []struct {
+		dst *string
+		src string
+	}{
+		{&h.Algorithm, u.Algorithm},
+		{&h.KeyID, u.KeyID},
+		{&h.X509SHA256Thumbprint, u.X509SHA256Thumbprint},
+		{&h.Type, u.Type},
+		{&h.ContentType, u.ContentType},
+	} {
+		if p.src == "" {
+			continue
+		}
+		if *p.dst != "" {
+			return nil, errors.New("jws: duplicate header parameter")
+		}
+		*p.dst = p.src
+	}
+	if len(u.JWK) > 0 {
+		if len(h.JWK) > 0 {
+			return nil, errors.New("jws: duplicate header parameter")
+		}
+		h.JWK = u.JWK
+	}
+
+	return h, nil
+}
+
+// verify checks sig over the message payload using key.
+func (msg *Message) verify(sig *Signature, key jwk.Key) error {
+	h, err := sig.header()
+	if err != nil {
+		return err
+	}
+	if h.Algorithm == "" {
+		return errors.New("jws: missing algorithm")
+	}
+	if len(h.Critical) > 0 {
+		return fmt.Errorf("jws: unsupported critical header parameters %q", h.Critical)
+	}
+
+	input := sig.protected + "." + encode(msg.Payload)
+	return verify(h.Algorithm, key, []byte(input), sig.Signature)
+}
+
+// encode returns the base64url encoding of data without padding.
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decode decodes base64url-encoded data without padding. Non-canonical
+// encodings are rejected, so that re-encoding gives the original input.
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.Strict().DecodeString(s)
+}
@@ -0,0 +1,199 @@
+package jws
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/ericyan/jwk"
+)
+
+var (
+	rsaTestKey, _       = rsa.GenerateKey(rand.Reader, 2048)
+	ecdsaTestKeyP256, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecdsaTestKeyP384, _ = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	ecdsaTestKeyP521, _ = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	hmacTestKey         = bytes.Repeat([]byte{0x42}, 64)
+)
+
+func mustKey(t *testing.T, key jwk.CryptoKey, params *jwk.Params) jwk.Key {
+	k, err := jwk.New(key, params)
+	if err != nil {
+		t.Fatal("failed to create key:", err)
+	}
+
+	return k
+}
+
+func TestRFC7515Examples(t *testing.T) {
+	// Test vectors from Appendix A.1 and A.3 of RFC 7515
+	cases := []struct {
+		jwk string
+		jws string
+	}{
+		{
+			jwk: `{"kty":"oct",
+				"k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"}`,
+			jws: "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9" +
+				".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+				".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk",
+		},
+		{
+			jwk: `{"kty":"EC",
+				"crv":"P-256",
+				"x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+				"y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}`,
+			jws: "eyJhbGciOiJFUzI1NiJ9" +
+				".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+				".DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q",
+		},
+	}
+
+	for i, c := range cases {
+		key, err := jwk.Parse([]byte(c.jwk))
+		if err != nil {
+			t.Fatalf("case %d: unexpected error: %v", i, err)
+		}
+
+		payload, err := Verify([]byte(c.jws), key)
+		if err != nil {
+			t.Errorf("case %d: failed to verify: %v", i, err)
+		}
+		if !bytes.HasPrefix(payload, []byte(`{"iss":"joe"`)) {
+			t.Errorf("case %d: unexpected payload: %s", i, payload)
+		}
+
+		tampered := []byte(c.jws)
+		tampered[len(tampered)-5] ^= 0x01
+		_, err = Verify(tampered, key)
+		if err == nil {
+			t.Errorf("case %d: expected error on tampered signature", i)
+		}
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	cases := []struct {
+		alg  string
+		key  jwk.CryptoKey
+		pub  jwk.CryptoKey
+		size int
+	}{
+		{HS256, hmacTestKey, hmacTestKey, 32},
+		{HS384, hmacTestKey, hmacTestKey, 48},
+		{HS512, hmacTestKey, hmacTestKey, 64},
+		{RS256, rsaTestKey, &rsaTestKey.PublicKey, 256},
+		{RS384, rsaTestKey, &rsaTestKey.PublicKey, 256},
+		{RS512, rsaTestKey, &rsaTestKey.PublicKey, 256},
+		{PS256, rsaTestKey, &rsaTestKey.PublicKey, 256},
+		{PS384, rsaTestKey, &rsaTestKey.PublicKey, 256},
+		{PS512, rsaTestKey, &rsaTestKey.PublicKey, 256},
+		{ES256, ecdsaTestKeyP256, &ecdsaTestKeyP256.PublicKey, 64},
+		{ES384, ecdsaTestKeyP384, &ecdsaTestKeyP384.PublicKey, 96},
+		{ES512, ecdsaTestKeyP521, &ecdsaTestKeyP521.PublicKey, 132},
+	}
+
+	payload := []byte("The true sign of intelligence is not knowledge but imagination.")
+	for _, c := range cases {
+		key := mustKey(t, c.key, &jwk.Params{KeyID: "foo", Algorithm: c.alg})
+		pub := mustKey(t, c.pub, nil)
+
+		data, err := Sign(payload, key, nil)
+		if err != nil {
+			t.Fatalf("%s: failed to sign: %v", c.alg, err)
+		}
+
+		msg, err := Parse(data)
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", c.alg, err)
+		}
+		h := msg.Signatures[0].Protected
+		if h.Algorithm != c.alg || h.KeyID != "foo" {
+			t.Errorf("%s: unexpected header: %+v", c.alg, h)
+		}
+		if len(msg.Signatures[0].Signature) != c.size {
+			t.Errorf("%s: unexpected signature size: %d", c.alg, len(msg.Signatures[0].Signature))
+		}
+
+		verified, err := Verify(data, pub)
+		if err != nil {
+			t.Errorf("%s: failed to verify: %v", c.alg, err)
+		}
+		if !bytes.Equal(verified, payload) {
+			t.Errorf("%s: payload mismatch", c.alg)
+		}
+	}
+}
+
+func TestSignErrors(t *testing.T) {
+	payload := []byte("foo")
+	cases := []struct {
+		name   string
+		key    jwk.Key
+		header *Header
+	}{
+		{"missing algorithm", mustKey(t, hmacTestKey, nil), nil},
+		{"unsupported algorithm", mustKey(t, hmacTestKey, nil), &Header{Algorithm: "none"}},
+		{"wrong key type", mustKey(t, hmacTestKey, nil), &Header{Algorithm: RS256}},
+		{"wrong curve", mustKey(t, ecdsaTestKeyP256, nil), &Header{Algorithm: ES384}},
+		{"algorithm mismatch", mustKey(t, rsaTestKey, &jwk.Params{Algorithm: RS256}), &Header{Algorithm: PS256}},
+		{"encryption key", mustKey(t, rsaTestKey, &jwk.Params{KeyUse: "enc"}), &Header{Algorithm: RS256}},
+		{"key ops", mustKey(t, rsaTestKey, &jwk.Params{KeyOps: []string{"verify"}}), &Header{Algorithm: RS256}},
+		{"public key", mustKey(t, &rsaTestKey.PublicKey, nil), &Header{Algorithm: RS256}},
+		{"short HMAC key", mustKey(t, []byte{1, 2, 3, 4}, nil), &Header{Algorithm: HS256}},
+	}
+
+	for _, c := range cases {
+		_, err := Sign(payload, c.key, c.header)
+		if err == nil {
+			t.Errorf("expected error on signing with %s", c.name)
+		}
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	key := mustKey(t, hmacTestKey, &jwk.Params{Algorithm: HS256})
+	data, err := Sign([]byte("foo"), key, &Header{Critical: []string{"exp"}})
+	if err != nil {
+		t.Fatal("failed to sign:", err)
+	}
+	_, err = Verify(data, key)
+	if err == nil {
+		t.Error("expected error on unsupported critical header")
+	}
+
+	for _, data := range []string{"", "a.b", "a.b.c.d", "eyJhbGciOiJub25lIn0.Zm9v.", "!.Zm9v.Zm9v"} {
+		_, err := Verify([]byte(data), key)
+		if err == nil {
+			t.Errorf("expected error on verifying '%s'", data)
+		}
+	}
+}
+
+func TestDestroyedKey(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x42}, 32)
+	key := mustKey(t, secret, &jwk.Params{Algorithm: HS256})
+	data, err := Sign([]byte("foo"), key, nil)
+	if err != nil {
+		t.Fatal("failed to sign:", err)
+	}
+
+	key.(jwk.Destroyer).Destroy()
+
+	if _, err := Verify(data, key); err == nil {
+		t.Error("expected error on verifying with destroyed key")
+	}
+	if _, err := Sign([]byte("foo"), key, nil); err == nil {
+		t.Error("expected error on signing with destroyed key")
+	}
+
+	// A MAC computed with the all-zero key must not be accepted either.
+	zero := mustKey(t, make([]byte, len(secret)), &jwk.Params{Algorithm: HS256})
+	data, _ = Sign([]byte("foo"), zero, nil)
+	if _, err := Verify(data, key); err == nil {
+		t.Error("expected error on verifying all-zero key MAC with destroyed key")
+	}
+}
@@ -0,0 +1,160 @@
+package jws
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ericyan/jwk"
+)
+
+// ErrNoMatchingKey is returned when no key can be resolved to verify a
+// signature.
+var ErrNoMatchingKey = errors.New("jws: no matching key")
+
+// Verifier verifies signatures using keys resolved from a Set.
+//
+// The key for a signature is resolved from its header in the following
+// order:
+//
+//  1. keys in Set whose key ID equals the kid header parameter;
+//  2. the key embedded in the jwk header parameter, if AllowEmbeddedKey
+//     accepts it;
+//  3. keys in Set whose x5t#S256 parameter equals the x5t#S256 header
+//     parameter.
+//
+// If the header carries none of these parameters, every key in Set is a
+// candidate. A key whose algorithm parameter is set is only used when it
+// matches the alg header parameter.
+type Verifier struct {
+	Set *jwk.Set
+
+	// AllowEmbeddedKey reports whether a public key embedded in the jwk
+	// header parameter may be trusted. If nil, embedded keys are never
+	// used.
+	AllowEmbeddedKey func(key jwk.Key) bool
+}
+
+// Verify verifies data in the JWS Compact Serialization and returns the
+// payload.
+func (v *Verifier) Verify(data []byte) ([]byte, error) {
+	msg, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.verify(msg, msg.Signatures[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.Payload, nil
+}
+
+// VerifyJSON verifies data in either JSON serialization according to
+// policy and returns the payload.
+func (v *Verifier) VerifyJSON(data []byte, policy Policy) ([]byte, error) {
+	msg, err := ParseJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := 0
+	for _, sig := range msg.Signatures {
+		err = v.verify(msg, sig)
+		if err == nil {
+			valid++
+		} else if policy == All {
+			return nil, err
+		}
+	}
+	if valid == 0 {
+		return nil, err
+	}
+
+	return msg.Payload, nil
+}
+
+// verify verifies sig using the keys resolved from its header.
+func (v *Verifier) verify(msg *Message, sig *Signature) error {
+	h, err := sig.header()
+	if err != nil {
+		return err
+	}
+
+	keys, err := v.resolve(h)
+	if err != nil {
+		return err
+	}
+
+	err = ErrNoMatchingKey
+	for _, key := range keys {
+		if key.Alg() != "" && key.Alg() != h.Algorithm {
+			continue
+		}
+
+		err = msg.verify(sig, key)
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// resolve returns the candidate keys for verifying a signature with
+// header h.
+func (v *Verifier) resolve(h *Header) ([]jwk.Key, error) {
+	var keys []jwk.Key
+	if v.Set != nil {
+		keys = v.Set.Keys
+	}
+
+	if h.KeyID == "" && len(h.JWK) == 0 && h.X509SHA256Thumbprint == "" {
+		return keys, nil
+	}
+
+	if h.KeyID != "" {
+		matched := filter(keys, func(key jwk.Key) bool {
+			return key.ID() == h.KeyID
+		})
+		if len(matched) > 0 {
+			return matched, nil
+		}
+	}
+
+	if len(h.JWK) > 0 && v.AllowEmbeddedKey != nil {
+		key, err := jwk.Parse(h.JWK)
+		if err != nil {
+			return nil, fmt.Errorf("jws: invalid embedded key: %v", err)
+		}
+		if key.IsPrivate() {
+			return nil, errors.New("jws: invalid embedded key, not a public key")
+		}
+		if v.AllowEmbeddedKey(key) {
+			return []jwk.Key{key}, nil
+		}
+	}
+
+	if h.X509SHA256Thumbprint != "" {
+		matched := filter(keys, func(key jwk.Key) bool {
+			return key.Parameters().X509SHA256Thumbprint == h.X509SHA256Thumbprint
+		})
+		if len(matched) > 0 {
+			return matched, nil
+		}
+	}
+
+	return nil, ErrNoMatchingKey
+}
+
+// filter returns the keys for which fn returns true.
+func filter(keys []jwk.Key, fn func(jwk.Key) bool) []jwk.Key {
+	var matched []jwk.Key
+	for _, key := range keys {
+		if fn(key) {
+			matched = append(matched, key)
+		}
+	}
+
+	return matched
+}
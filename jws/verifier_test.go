@@ -0,0 +1,92 @@
+package jws
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ericyan/jwk"
+)
+
+func TestVerifier(t *testing.T) {
+	rsaKey := mustKey(t, rsaTestKey, &jwk.Params{KeyID: "rsa"})
+	ecKey := mustKey(t, ecdsaTestKeyP256, &jwk.Params{KeyID: "ec", X509SHA256Thumbprint: "Zm9v"})
+	rsaPub := mustKey(t, &rsaTestKey.PublicKey, &jwk.Params{KeyID: "rsa", Algorithm: RS256})
+	ecPub := mustKey(t, &ecdsaTestKeyP256.PublicKey, &jwk.Params{KeyID: "ec", X509SHA256Thumbprint: "Zm9v"})
+	v := &Verifier{Set: &jwk.Set{Keys: []jwk.Key{rsaPub, ecPub}}}
+
+	embedded, _ := json.Marshal(ecPub)
+	cases := []struct {
+		name   string
+		key    jwk.Key
+		header *Header
+		valid  bool
+	}{
+		{"kid", rsaKey, &Header{Algorithm: RS256}, true},
+		{"kid with alg mismatch", rsaKey, &Header{Algorithm: PS256}, false},
+		{"unknown kid", rsaKey, &Header{Algorithm: RS256, KeyID: "foo"}, false},
+		{"x5t#S256", ecKey, &Header{Algorithm: ES256, KeyID: "foo", X509SHA256Thumbprint: "Zm9v"}, true},
+		{"x5t#S256 mismatch", ecKey, &Header{Algorithm: ES256, KeyID: "foo", X509SHA256Thumbprint: "YmFy"}, false},
+		{"embedded key not allowed", ecKey, &Header{Algorithm: ES256, KeyID: "foo", JWK: embedded}, false},
+	}
+
+	for _, c := range cases {
+		data, err := Sign([]byte("foo"), c.key, c.header)
+		if err != nil {
+			t.Fatalf("%s: failed to sign: %v", c.name, err)
+		}
+
+		_, err = v.Verify(data)
+		if c.valid && err != nil {
+			t.Errorf("%s: failed to verify: %v", c.name, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
+
+func TestVerifierEmbeddedKey(t *testing.T) {
+	key := mustKey(t, ecdsaTestKeyP256, nil)
+	pub := mustKey(t, &ecdsaTestKeyP256.PublicKey, nil)
+	embedded, _ := json.Marshal(pub)
+
+	data, err := Sign([]byte("foo"), key, &Header{Algorithm: ES256, JWK: embedded})
+	if err != nil {
+		t.Fatal("failed to sign:", err)
+	}
+
+	trusted := &Verifier{AllowEmbeddedKey: func(k jwk.Key) bool { return jwk.Equal(k, pub) }}
+	_, err = trusted.Verify(data)
+	if err != nil {
+		t.Error("failed to verify with trusted embedded key:", err)
+	}
+
+	untrusted := &Verifier{AllowEmbeddedKey: func(k jwk.Key) bool { return false }}
+	_, err = untrusted.Verify(data)
+	if err == nil {
+		t.Error("expected error on untrusted embedded key")
+	}
+
+	priv, _ := jwk.MarshalPrivate(key)
+	data, _ = Sign([]byte("foo"), key, &Header{Algorithm: ES256, JWK: priv})
+	_, err = trusted.Verify(data)
+	if err == nil {
+		t.Error("expected error on embedded private key")
+	}
+}
+
+func TestVerifierWithoutHints(t *testing.T) {
+	key := mustKey(t, hmacTestKey, nil)
+	other := mustKey(t, append([]byte{0}, hmacTestKey...), nil)
+	v := &Verifier{Set: &jwk.Set{Keys: []jwk.Key{other, key}}}
+
+	data, err := Sign([]byte("foo"), key, &Header{Algorithm: HS256})
+	if err != nil {
+		t.Fatal("failed to sign:", err)
+	}
+
+	_, err = v.Verify(data)
+	if err != nil {
+		t.Error("failed to verify:", err)
+	}
+}
@@ -0,0 +1,159 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+)
+
+// Errors returned when validating registered claims.
+var (
+	ErrExpired         = errors.New("jwt: token is expired")
+	ErrNotValidYet     = errors.New("jwt: token is not valid yet")
+	ErrIssuedInFuture  = errors.New("jwt: token is issued in the future")
+	ErrInvalidIssuer   = errors.New("jwt: invalid issuer")
+	ErrInvalidAudience = errors.New("jwt: invalid audience")
+	ErrInvalidSubject  = errors.New("jwt: invalid subject")
+)
+
+// NumericDate represents a JSON numeric date value, as defined in RFC
+// 7519, Section 2.
+type NumericDate struct {
+	time.Time
+}
+
+// NewNumericDate creates a new NumericDate, truncated to seconds.
+func NewNumericDate(t time.Time) *NumericDate {
+	return &NumericDate{t.Truncate(time.Second)}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d *NumericDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Unix())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *NumericDate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var f float64
+	err := json.Unmarshal(data, &f)
+	if err != nil {
+		return errors.New("jwt: invalid numeric date")
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) || math.Abs(f) > 1<<53 {
+		return errors.New("jwt: invalid numeric date")
+	}
+
+	sec, frac := math.Modf(f)
+	d.Time = time.Unix(int64(sec), int64(frac*1e9))
+	return nil
+}
+
+// Audience represents the audience claim, which is either a single string
+// or an array of strings.
+type Audience []string
+
+// Contains reports whether aud is in the audience.
+func (a Audience) Contains(aud string) bool {
+	for _, v := range a {
+		if v == aud {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface. A single audience
+// is serialized as a string.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. A null
+// audience is decoded as nil.
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*a = nil
+		return nil
+	}
+
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		*a = Audience{s}
+		return nil
+	}
+
+	var list []string
+	err := json.Unmarshal(data, &list)
+	if err != nil {
+		return errors.New("jwt: invalid audience claim")
+	}
+
+	*a = list
+	return nil
+}
+
+// Claims represents the registered claims defined in RFC 7519, Section
+// 4.1. It is meant to be embedded in application-specific claim types.
+type Claims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  Audience     `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+// Validator validates registered claims. Empty expected values are not
+// checked.
+type Validator struct {
+	Issuer   string
+	Audience string
+	Subject  string
+
+	// Leeway is the allowed clock skew when validating exp, nbf and iat.
+	Leeway time.Duration
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// Validate validates the registered claims c.
+func (v *Validator) Validate(c *Claims) error {
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+
+	if c.ExpiresAt != nil && !now.Before(c.ExpiresAt.Add(v.Leeway)) {
+		return ErrExpired
+	}
+	if c.NotBefore != nil && now.Add(v.Leeway).Before(c.NotBefore.Time) {
+		return ErrNotValidYet
+	}
+	if c.IssuedAt != nil && now.Add(v.Leeway).Before(c.IssuedAt.Time) {
+		return ErrIssuedInFuture
+	}
+
+	if v.Issuer != "" && c.Issuer != v.Issuer {
+		return ErrInvalidIssuer
+	}
+	if v.Audience != "" && !c.Audience.Contains(v.Audience) {
+		return ErrInvalidAudience
+	}
+	if v.Subject != "" && c.Subject != v.Subject {
+		return ErrInvalidSubject
+	}
+
+	return nil
+}
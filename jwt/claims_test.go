@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNumericDate(t *testing.T) {
+	var d NumericDate
+	err := json.Unmarshal([]byte("1300819380.5"), &d)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if d.Unix() != 1300819380 || d.Nanosecond() != 5e8 {
+		t.Error("unexpected time:", d.Time)
+	}
+
+	data, _ := json.Marshal(NewNumericDate(d.Time))
+	if string(data) != "1300819380" {
+		t.Error("unexpected encoding:", string(data))
+	}
+
+	for _, data := range []string{`"1300819380"`, `1e300`, `true`} {
+		err := json.Unmarshal([]byte(data), new(NumericDate))
+		if err == nil {
+			t.Errorf("expected error on decoding '%s'", data)
+		}
+	}
+}
+
+func TestAudience(t *testing.T) {
+	cases := []struct {
+		data string
+		aud  Audience
+	}{
+		{`"foo"`, Audience{"foo"}},
+		{`["foo","bar"]`, Audience{"foo", "bar"}},
+	}
+
+	for _, c := range cases {
+		var aud Audience
+		err := json.Unmarshal([]byte(c.data), &aud)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if len(aud) != len(c.aud) || !aud.Contains(c.aud[0]) {
+			t.Errorf("unexpected audience for %s: %v", c.data, aud)
+		}
+
+		data, _ := json.Marshal(aud)
+		if string(data) != c.data {
+			t.Errorf("unexpected encoding: %s", data)
+		}
+	}
+
+	err := json.Unmarshal([]byte(`42`), new(Audience))
+	if err == nil {
+		t.Error("expected error on invalid audience")
+	}
+	var claims Claims
+	err = json.Unmarshal([]byte(`{"iss":"joe","aud":null}`), &claims)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if claims.Audience != nil {
+		t.Errorf("unexpected audience for null: %q", claims.Audience)
+	}
+	if data, _ := json.Marshal(claims); strings.Contains(string(data), "aud") {
+		t.Errorf("unexpected encoding: %s", data)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	now := time.Unix(1300819380, 0)
+	at := func(offset time.Duration) *NumericDate { return NewNumericDate(now.Add(offset)) }
+	v := Validator{
+		Issuer:   "joe",
+		Audience: "bob",
+		Leeway:   time.Minute,
+		Now:      func() time.Time { return now },
+	}
+
+	cases := []struct {
+		claims Claims
+		err    error
+	}{
+		{Claims{Issuer: "joe", Audience: Audience{"alice", "bob"}, ExpiresAt: at(time.Hour)}, nil},
+		{Claims{Issuer: "joe", Audience: Audience{"bob"}, ExpiresAt: at(-30 * time.Second)}, nil},
+		{Claims{Issuer: "joe", Audience: Audience{"bob"}, ExpiresAt: at(-time.Minute)}, ErrExpired},
+		{Claims{Issuer: "joe", Audience: Audience{"bob"}, NotBefore: at(30 * time.Second)}, nil},
+		{Claims{Issuer: "joe", Audience: Audience{"bob"}, NotBefore: at(2 * time.Minute)}, ErrNotValidYet},
+		{Claims{Issuer: "joe", Audience: Audience{"bob"}, IssuedAt: at(2 * time.Minute)}, ErrIssuedInFuture},
+		{Claims{Issuer: "eve", Audience: Audience{"bob"}}, ErrInvalidIssuer},
+		{Claims{Issuer: "joe", Audience: Audience{"alice"}}, ErrInvalidAudience},
+		{Claims{Issuer: "joe"}, ErrInvalidAudience},
+	}
+
+	for i, c := range cases {
+		err := v.Validate(&c.claims)
+		if err != c.err {
+			t.Errorf("case %d: expected %v, got %v", i, c.err, err)
+		}
+	}
+
+	v.Subject = "alice"
+	err := v.Validate(&Claims{Issuer: "joe", Audience: Audience{"bob"}, Subject: "eve"})
+	if err != ErrInvalidSubject {
+		t.Error("expected invalid subject error, got", err)
+	}
+}
@@ -0,0 +1,75 @@
+// Package jwt implements signed JSON Web Tokens (RFC 7519) using JSON Web
+// Keys.
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ericyan/jwk"
+	"github.com/ericyan/jwk/jws"
+)
+
+// Sign serializes claims as JSON and signs them using key, returning the
+// JWT in the JWS Compact Serialization. The algorithm of the key is used.
+//
+// Claims is typically a struct embedding Claims.
+func Sign(claims interface{}, key jwk.Key) ([]byte, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return jws.Sign(payload, key, &jws.Header{Type: "JWT"})
+}
+
+// Parse verifies token using the keys in set, decodes its claims into
+// claims and validates the registered claims using v. If v is nil, only
+// exp, nbf and iat are validated.
+//
+// Claims is typically a pointer to a struct embedding Claims, and may be
+// nil if only the registered claims are of interest.
+func Parse(token []byte, set *jwk.Set, claims interface{}, v *Validator) error {
+	msg, err := jws.Parse(token)
+	if err != nil {
+		return err
+	}
+
+	h := msg.Signatures[0].Protected
+	if h.Type != "" && !strings.EqualFold(h.Type, "JWT") {
+		return fmt.Errorf("jwt: unexpected type '%s'", h.Type)
+	}
+	if h.ContentType != "" {
+		return errors.New("jwt: nested tokens not supported")
+	}
+
+	payload, err := (&jws.Verifier{Set: set}).Verify(token)
+	if err != nil {
+		return err
+	}
+
+	registered := new(Claims)
+	err = json.Unmarshal(payload, registered)
+	if err != nil {
+		return fmt.Errorf("jwt: invalid claims: %v", err)
+	}
+
+	if v == nil {
+		v = new(Validator)
+	}
+	err = v.Validate(registered)
+	if err != nil {
+		return err
+	}
+
+	if claims != nil {
+		err = json.Unmarshal(payload, claims)
+		if err != nil {
+			return fmt.Errorf("jwt: invalid claims: %v", err)
+		}
+	}
+
+	return nil
+}
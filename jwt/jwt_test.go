@@ -0,0 +1,106 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/ericyan/jwk"
+	"github.com/ericyan/jwk/jws"
+)
+
+type testClaims struct {
+	Claims
+	Admin bool `json:"http://example.com/is_root"`
+}
+
+func TestRFC7519Example(t *testing.T) {
+	// Test vector from Section 3.1 of RFC 7519
+	key, err := jwk.Parse([]byte(`{"kty":"oct",
+		"k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	set := &jwk.Set{Keys: []jwk.Key{key}}
+
+	token := []byte("eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+		".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+
+	var claims testClaims
+	v := &Validator{
+		Issuer: "joe",
+		Now:    func() time.Time { return time.Unix(1300819379, 0) },
+	}
+	err = Parse(token, set, &claims, v)
+	if err != nil {
+		t.Fatal("failed to parse:", err)
+	}
+	if claims.Issuer != "joe" || claims.ExpiresAt.Unix() != 1300819380 || !claims.Admin {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	v.Now = func() time.Time { return time.Unix(1300819380, 0) }
+	err = Parse(token, set, &claims, v)
+	if err != ErrExpired {
+		t.Error("expected expired error, got", err)
+	}
+}
+
+func TestSignAndParse(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	key, _ := jwk.New(priv, &jwk.Params{KeyID: "foo", Algorithm: jws.ES256})
+	pub, _ := jwk.New(&priv.PublicKey, &jwk.Params{KeyID: "foo", Algorithm: jws.ES256})
+	set := &jwk.Set{Keys: []jwk.Key{pub}}
+
+	now := time.Now()
+	token, err := Sign(&testClaims{
+		Claims: Claims{
+			Issuer:    "https://issuer.example.com",
+			Subject:   "alice",
+			Audience:  Audience{"https://api.example.com"},
+			IssuedAt:  NewNumericDate(now),
+			ExpiresAt: NewNumericDate(now.Add(time.Hour)),
+		},
+		Admin: true,
+	}, key)
+	if err != nil {
+		t.Fatal("failed to sign:", err)
+	}
+
+	msg, _ := jws.Parse(token)
+	if h := msg.Signatures[0].Protected; h.Type != "JWT" || h.KeyID != "foo" {
+		t.Errorf("unexpected header: %+v", h)
+	}
+
+	var claims testClaims
+	err = Parse(token, set, &claims, &Validator{
+		Issuer:   "https://issuer.example.com",
+		Audience: "https://api.example.com",
+		Subject:  "alice",
+	})
+	if err != nil {
+		t.Fatal("failed to parse:", err)
+	}
+	if !claims.Admin || claims.Subject != "alice" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	err = Parse(token, set, nil, &Validator{Audience: "https://other.example.com"})
+	if err != ErrInvalidAudience {
+		t.Error("expected invalid audience error, got", err)
+	}
+
+	err = Parse(token, &jwk.Set{}, nil, nil)
+	if err == nil {
+		t.Error("expected error on verifying with empty set")
+	}
+
+	other, _ := jws.Sign([]byte(`{}`), key, &jws.Header{Type: "at+jwt+foo"})
+	err = Parse(other, set, nil, nil)
+	if err == nil {
+		t.Error("expected error on unexpected type")
+	}
+}
@@ -0,0 +1,374 @@
+// Package keystore loads keys from the file system and reloads them when
+// they change, as happens with Kubernetes-mounted secrets.
+package keystore
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericyan/jwk"
+)
+
+// File name extensions recognized when loading a directory.
+var (
+	jsonExts = []string{".json", ".jwk", ".jwks"}
+	pemExts  = []string{".pem", ".key", ".crt"}
+)
+
+// Store holds the keys loaded from Path, which is either a file holding
+// a JWK or a JWK Set, or a directory of JWK, JWK Set and PEM files.
+// Hidden files and files with other extensions in a directory are
+// ignored. Keys from PEM files without a key ID are identified by their
+// JWK Thumbprint.
+//
+// The loaded keys are swapped in atomically, and only once they have
+// passed validation, so a Store keeps serving the last good set when
+// the files are broken or half-written.
+//
+// Store implements the jwk.Provider interface. It is safe for concurrent
+// use.
+type Store struct {
+	Path string
+
+	// Validate, if not nil, is called on newly loaded sets, in addition
+	// to the built-in checks that the set is not empty and that key IDs
+	// are unique. A set is rejected if Validate returns an error.
+	Validate func(*jwk.Set) error
+
+	// OnError, if not nil, is called by Watch when reloading fails.
+	OnError func(error)
+
+	mu     sync.RWMutex
+	set    *jwk.Set
+	digest [sha256.Size]byte
+	err    error
+}
+
+// Open creates a Store and loads its keys.
+func Open(path string) (*Store, error) {
+	s := &Store{Path: path}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// KeySet returns the last good set, which must not be modified.
+func (s *Store) KeySet() (*jwk.Set, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.set == nil {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, errors.New("keystore: not loaded")
+	}
+
+	return s.set, nil
+}
+
+// Err returns the error of the last reload, if it failed.
+func (s *Store) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.err
+}
+
+// Reload reads the files and, if their content has changed, validates
+// and swaps in the new set. It reports whether the set was replaced.
+func (s *Store) Reload() (bool, error) {
+	files, err := readFiles(s.Path)
+	if err == nil && len(files) == 0 {
+		err = errors.New("keystore: no key files found")
+	}
+	if err != nil {
+		return false, s.fail(err)
+	}
+
+	h := sha256.New()
+	for _, f := range files {
+		fmt.Fprintf(h, "%s\x00%d\x00", f.name, len(f.data))
+		h.Write(f.data)
+	}
+	var digest [sha256.Size]byte
+	copy(digest[:], h.Sum(nil))
+
+	s.mu.RLock()
+	unchanged := s.set != nil && s.err == nil && digest == s.digest
+	s.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	set, err := s.load(files)
+	if err != nil {
+		return false, s.fail(err)
+	}
+
+	s.mu.Lock()
+	s.set, s.digest, s.err = set, digest, nil
+	s.mu.Unlock()
+
+	return true, nil
+}
+
+func (s *Store) fail(err error) error {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+
+	return err
+}
+
+// Watch polls for changes at every interval until ctx is done. Failed
+// reloads are reported to OnError and otherwise ignored.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := s.Reload(); err != nil && s.OnError != nil {
+				s.OnError(err)
+			}
+		}
+	}
+}
+
+type file struct {
+	name string
+	data []byte
+}
+
+// readFiles reads path, or the key files in it if it is a directory,
+// sorted by name.
+func readFiles(path string) ([]file, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fi.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return []file{{filepath.Base(path), data}}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []file
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") || !(hasExt(name, jsonExts) || hasExt(name, pemExts)) {
+			continue
+		}
+
+		// Follow symlinks, which is how Kubernetes projects secrets.
+		fi, err := os.Stat(filepath.Join(path, name))
+		if err != nil {
+			return nil, err
+		}
+		if !fi.Mode().IsRegular() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file{name, data})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+
+	return files, nil
+}
+
+func hasExt(name string, exts []string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range exts {
+		if ext == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// load parses and validates the files.
+func (s *Store) load(files []file) (*jwk.Set, error) {
+	set := &jwk.Set{Keys: []jwk.Key{}}
+	for _, f := range files {
+		var keys []jwk.Key
+		var err error
+		if hasExt(f.name, pemExts) {
+			keys, err = parsePEM(f.data)
+		} else {
+			keys, err = parseJSON(f.data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("keystore: %s: %v", f.name, err)
+		}
+		set.Keys = append(set.Keys, keys...)
+	}
+
+	if len(set.Keys) == 0 {
+		return nil, errors.New("keystore: no keys found")
+	}
+
+	keys, err := dedup(set.Keys)
+	if err != nil {
+		return nil, err
+	}
+	set.Keys = keys
+
+	if s.Validate != nil {
+		if err := s.Validate(set); err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+// dedup merges keys sharing a key ID if they are halves of the same key
+// pair, such as a private key and its certificate, keeping the private
+// key. Any other duplicate key ID is an error.
+func dedup(keys []jwk.Key) ([]jwk.Key, error) {
+	var result []jwk.Key
+	index := make(map[string]int)
+	for _, key := range keys {
+		i, ok := index[key.ID()]
+		if !ok {
+			index[key.ID()] = len(result)
+			result = append(result, key)
+			continue
+		}
+
+		a, errA := jwk.Thumbprint(result[i], crypto.SHA256)
+		b, errB := jwk.Thumbprint(key, crypto.SHA256)
+		if errA != nil || errB != nil || string(a) != string(b) || result[i].IsPrivate() == key.IsPrivate() {
+			return nil, fmt.Errorf("keystore: duplicate key ID %q", key.ID())
+		}
+
+		priv, pub := result[i], key
+		if pub.IsPrivate() {
+			priv, pub = pub, priv
+		}
+		if p := priv.Parameters(); p.X509SHA256Thumbprint == "" {
+			p.X509SHA256Thumbprint = pub.Parameters().X509SHA256Thumbprint
+		}
+		result[i] = priv
+	}
+
+	return result, nil
+}
+
+func parseJSON(data []byte) ([]jwk.Key, error) {
+	var probe struct {
+		Keys json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	if probe.Keys == nil {
+		key, err := jwk.Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		return []jwk.Key{key}, nil
+	}
+
+	set := new(jwk.Set)
+	if err := json.Unmarshal(data, set); err != nil {
+		return nil, err
+	}
+
+	return set.Keys, nil
+}
+
+// parsePEM parses the keys and certificates in data. A certificate is
+// only used for the public key it contains, and sets the x5t#S256
+// parameter.
+func parsePEM(data []byte) ([]jwk.Key, error) {
+	var keys []jwk.Key
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		var ck interface{}
+		var err error
+		params := new(jwk.Params)
+		switch block.Type {
+		case "PRIVATE KEY":
+			ck, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			ck, err = x509.ParseECPrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			ck, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "PUBLIC KEY":
+			ck, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			ck, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			cert, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				sum := sha256.Sum256(cert.Raw)
+				params.X509SHA256Thumbprint = base64.RawURLEncoding.EncodeToString(sum[:])
+				ck = cert.PublicKey
+			}
+		default:
+			return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := jwk.New(ck, params)
+		if err != nil {
+			return nil, err
+		}
+
+		tp, err := jwk.Thumbprint(key, crypto.SHA256)
+		if err != nil {
+			return nil, err
+		}
+		params.KeyID = base64.RawURLEncoding.EncodeToString(tp)
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM blocks found")
+	}
+
+	return keys, nil
+}
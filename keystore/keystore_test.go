@@ -0,0 +1,193 @@
+package keystore
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ericyan/jwk"
+)
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testJWK(t *testing.T, kid string) []byte {
+	t.Helper()
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	key, _ := jwk.New(priv, &jwk.Params{KeyID: kid})
+	data, err := jwk.MarshalPrivate(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestStoreFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeFile(t, path, []byte(`{"keys":[`+string(testJWK(t, "a"))+`]}`))
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal("failed to open store:", err)
+	}
+
+	set, err := s.KeySet()
+	if err != nil || len(set.Keys) != 1 || set.Keys[0].ID() != "a" {
+		t.Fatal("unexpected key set")
+	}
+
+	if changed, err := s.Reload(); changed || err != nil {
+		t.Error("unexpected reload of unchanged file")
+	}
+
+	// Broken content is rejected and the last good set is kept.
+	for _, data := range []string{
+		`{"keys":[`,
+		`{"keys":[]}`,
+		`{"keys":[` + string(testJWK(t, "b")) + `,` + string(testJWK(t, "b")) + `]}`,
+	} {
+		writeFile(t, path, []byte(data))
+		if _, err := s.Reload(); err == nil {
+			t.Errorf("expected error on reloading %s", data)
+		}
+		if s.Err() == nil {
+			t.Error("reload error not recorded")
+		}
+		if got, _ := s.KeySet(); got != set {
+			t.Error("last good set not kept")
+		}
+	}
+
+	writeFile(t, path, testJWK(t, "c"))
+	if changed, err := s.Reload(); !changed || err != nil {
+		t.Fatal("failed to reload changed file:", err)
+	}
+	if set, _ := s.KeySet(); set.Keys[0].ID() != "c" || s.Err() != nil {
+		t.Error("new set not swapped in")
+	}
+}
+
+func TestStoreDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(priv)
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1)}
+	cert, _ := x509.CreateCertificate(rand.Reader, tmpl, tmpl, priv.Public(), priv)
+
+	// A Kubernetes-style TLS secret: the certificate and key describe the
+	// same key pair and are merged.
+	data := filepath.Join(dir, "..data")
+	os.Mkdir(data, 0700)
+	writeFile(t, filepath.Join(data, "tls.key"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	writeFile(t, filepath.Join(data, "tls.crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}))
+	os.Symlink(filepath.Join("..data", "tls.key"), filepath.Join(dir, "tls.key"))
+	os.Symlink(filepath.Join("..data", "tls.crt"), filepath.Join(dir, "tls.crt"))
+
+	writeFile(t, filepath.Join(dir, "signing.jwk"), testJWK(t, "signing"))
+	writeFile(t, filepath.Join(dir, "README"), []byte("ignored"))
+
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal("failed to open store:", err)
+	}
+
+	set, _ := s.KeySet()
+	if len(set.Keys) != 2 {
+		t.Fatalf("unexpected number of keys: %d", len(set.Keys))
+	}
+
+	var tlsKey jwk.Key
+	for _, key := range set.Keys {
+		if key.ID() != "signing" {
+			tlsKey = key
+		}
+	}
+	if tlsKey == nil || !tlsKey.IsPrivate() || tlsKey.Parameters().X509SHA256Thumbprint == "" {
+		t.Error("certificate and private key not merged")
+	}
+
+	// A different key with the same ID is rejected.
+	writeFile(t, filepath.Join(dir, "other.jwk"), testJWK(t, "signing"))
+	if _, err := s.Reload(); err == nil {
+		t.Error("expected error on duplicate key ID")
+	}
+}
+
+func TestStoreValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.json")
+	writeFile(t, path, testJWK(t, "a"))
+
+	s := &Store{Path: path, Validate: func(set *jwk.Set) error {
+		for _, key := range set.Keys {
+			if key.ID() != "a" {
+				return errors.New("unexpected key")
+			}
+		}
+		return nil
+	}}
+	if _, err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, path, testJWK(t, "b"))
+	if _, err := s.Reload(); err == nil {
+		t.Error("expected validation error")
+	}
+}
+
+func TestStoreWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.json")
+	if _, err := Open(path); err == nil {
+		t.Error("expected error on opening missing file")
+	}
+
+	s := &Store{Path: path}
+	if _, err := s.KeySet(); err == nil {
+		t.Error("expected error before first load")
+	}
+
+	errs := make(chan error, 10)
+	s.OnError = func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Watch(ctx, 10*time.Millisecond) }()
+
+	<-errs
+	writeFile(t, path, testJWK(t, "a"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := s.KeySet(); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("watch did not load the key")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Error("unexpected error:", err)
+	}
+}
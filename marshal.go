@@ -0,0 +1,100 @@
+package jwk
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrPrivateKeyMarshal is returned when marshaling a symmetric key with
+// json.Marshal, which would expose its secret material.
+var ErrPrivateKeyMarshal = errors.New("jwk: symmetric keys must be marshaled with MarshalPrivate")
+
+// The plain types have the same members as the key types, but none of
+// their methods, so they marshal every member.
+type (
+	plainECDSAPrivateKey   ECDSAPrivateKey
+	plainRSAPrivateKey     RSAPrivateKey
+	plainEd25519PrivateKey Ed25519PrivateKey
+	plainOctetSequenceKey  OctetSequenceKey
+)
+
+// MarshalPrivate returns the JSON encoding of key, including its private
+// members. Unlike json.Marshal, which only ever emits public members,
+// it is meant for storing keys, never for publishing them.
+func MarshalPrivate(key Key) ([]byte, error) {
+	if key.IsPrivate() && key.CryptoKey() == nil {
+		return nil, ErrDestroyed
+	}
+
+	switch k := key.(type) {
+	case *ECDSAPrivateKey:
+		return json.Marshal((*plainECDSAPrivateKey)(k))
+	case *RSAPrivateKey:
+		return json.Marshal((*plainRSAPrivateKey)(k))
+	case *Ed25519PrivateKey:
+		return json.Marshal((*plainEd25519PrivateKey)(k))
+	case *OctetSequenceKey:
+		return json.Marshal((*plainOctetSequenceKey)(k))
+	case *SignerKey:
+		return nil, errors.New("jwk: private key material is not extractable")
+	default:
+		return json.Marshal(key)
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface. Only the public
+// members are marshaled, as with PublicKey; use MarshalPrivate to
+// include the private members.
+func (key *ECDSAPrivateKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(key.PublicKey())
+}
+
+// MarshalJSON implements the json.Marshaler interface. Only the public
+// members are marshaled, as with PublicKey; use MarshalPrivate to
+// include the private members.
+func (key *RSAPrivateKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(key.PublicKey())
+}
+
+// MarshalJSON implements the json.Marshaler interface. Only the public
+// members are marshaled, as with PublicKey; use MarshalPrivate to
+// include the private members.
+func (key *Ed25519PrivateKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(key.PublicKey())
+}
+
+// MarshalJSON implements the json.Marshaler interface. Symmetric keys
+// have no public members, so it always returns ErrPrivateKeyMarshal;
+// use MarshalPrivate instead.
+func (key *OctetSequenceKey) MarshalJSON() ([]byte, error) {
+	return nil, ErrPrivateKeyMarshal
+}
+
+// MarshalJSON implements the json.Marshaler interface. Only public keys
+// are marshaled, as with Public; use MarshalPrivate to include private
+// keys and members.
+//
+// It has a value receiver so that a Set is never marshaled member by
+// member.
+func (s Set) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Keys []Key `json:"keys"`
+	}{s.Public().Keys})
+}
+
+// MarshalPrivate returns the JSON encoding of the set, including private
+// keys and members. See MarshalPrivate.
+func (s *Set) MarshalPrivate() ([]byte, error) {
+	keys := make([]json.RawMessage, len(s.Keys))
+	for i, key := range s.Keys {
+		data, err := MarshalPrivate(key)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = data
+	}
+
+	return json.Marshal(struct {
+		Keys []json.RawMessage `json:"keys"`
+	}{keys})
+}
@@ -0,0 +1,105 @@
+package jwk
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMarshalPublicOnly(t *testing.T) {
+	ec, _ := NewECDSAPrivateKey(ecdsaTestKeyP256, &Params{KeyID: "ec", KeyOps: []string{"sign"}})
+	rsaKey, _ := NewRSAPrivateKey(rsaTestKey, &Params{KeyID: "rsa"})
+	ed, _ := NewEd25519PrivateKey(ed25519TestKey, &Params{KeyID: "ed"})
+
+	for _, key := range []Key{ec, rsaKey, ed} {
+		data, err := json.Marshal(key)
+		if err != nil {
+			t.Fatalf("failed to marshal %s key: %s", key.ID(), err)
+		}
+
+		var members map[string]interface{}
+		json.Unmarshal(data, &members)
+		for _, m := range []string{"d", "p", "q", "dp", "dq", "qi"} {
+			if _, ok := members[m]; ok {
+				t.Errorf("%s: private member %q marshaled", key.ID(), m)
+			}
+		}
+
+		parsed, err := Parse(data)
+		if err != nil || parsed.IsPrivate() || !Equal(parsed, key.(interface{ PublicKey() Key }).PublicKey()) {
+			t.Errorf("%s: marshaled key is not the public key", key.ID())
+		}
+	}
+
+	data, _ := json.Marshal(ec)
+	if !strings.Contains(string(data), `"key_ops":["verify"]`) {
+		t.Error("key_ops not remapped:", string(data))
+	}
+}
+
+func TestMarshalPrivate(t *testing.T) {
+	key, _ := NewRSAPrivateKey(rsaTestKey, &Params{KeyID: "rsa"})
+
+	data, err := MarshalPrivate(key)
+	if err != nil {
+		t.Fatal("failed to marshal private key:", err)
+	}
+
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatal("failed to parse private key:", err)
+	}
+	if !EqualWithParams(key, parsed) {
+		t.Error("round-trip of private key gave different keys")
+	}
+
+	pub := key.PublicKey()
+	data, err = MarshalPrivate(pub)
+	if err != nil {
+		t.Fatal("failed to marshal public key:", err)
+	}
+	if parsed, err := Parse(data); err != nil || !EqualWithParams(pub, parsed) {
+		t.Error("round-trip of public key gave different keys")
+	}
+
+	signer, _ := NewSignerKey(&opaqueSigner{ecdsaTestKeyP256}, nil)
+	if _, err := MarshalPrivate(signer); err == nil {
+		t.Error("expected error on marshaling opaque signer key")
+	}
+}
+
+func TestSetMarshal(t *testing.T) {
+	ec, _ := NewECDSAPrivateKey(ecdsaTestKeyP256, &Params{KeyID: "ec"})
+	oct, _ := NewOctetSequenceKey([]byte("secret"), &Params{KeyID: "oct"})
+	pub, _ := NewRSAPublicKey(&rsaTestKey.PublicKey, &Params{KeyID: "rsa"})
+	set := &Set{Keys: []Key{ec, oct, pub}}
+
+	// Both pointer and value receivers must marshal public keys only.
+	for _, v := range []interface{}{set, *set, struct{ S *Set }{set}} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal("failed to marshal set:", err)
+		}
+		if strings.Contains(string(data), `"d"`) || strings.Contains(string(data), `"oct"`) {
+			t.Error("private material in marshaled set:", string(data))
+		}
+	}
+
+	data, err := set.MarshalPrivate()
+	if err != nil {
+		t.Fatal("failed to marshal private set:", err)
+	}
+
+	parsed := new(Set)
+	if err := json.Unmarshal(data, parsed); err != nil {
+		t.Fatal("failed to parse private set:", err)
+	}
+	if len(parsed.Keys) != 3 {
+		t.Fatal("unexpected number of keys:", len(parsed.Keys))
+	}
+	for i, key := range set.Keys {
+		if !EqualWithParams(key, parsed.Keys[i]) {
+			t.Errorf("round-trip of key %s gave different keys", key.ID())
+		}
+	}
+}
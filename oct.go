@@ -1,8 +1,7 @@
 package jwk
 
 import (
-	"encoding/json"
-	"errors"
+	"fmt"
 
 	"github.com/ericyan/jwk/internal/base64url"
 )
@@ -26,10 +25,10 @@ func NewOctetSequenceKey(key []byte, params *Params) (*OctetSequenceKey, error)
 	}
 
 	if len(key) == 0 {
-		return nil, errors.New("jwk: invalid crypto key, zero length")
+		return nil, fmt.Errorf("%w: zero length", ErrInvalidCryptoKey)
 	}
 	if params.KeyType != TypeOCT {
-		return nil, errors.New("jwk: invalid params, wrong key type")
+		return nil, ErrWrongKeyType
 	}
 
 	return &OctetSequenceKey{params, base64url.NewValue(key)}, nil
@@ -38,22 +37,33 @@ func NewOctetSequenceKey(key []byte, params *Params) (*OctetSequenceKey, error)
 // Parse parses the JSON Web Key as an octet sequence key.
 func ParseOctetSequenceKey(jwk []byte) (*OctetSequenceKey, error) {
 	key := new(OctetSequenceKey)
-	err := json.Unmarshal(jwk, key)
+	err := unmarshalKey(jwk, key, "k")
 	if err != nil {
 		return nil, err
 	}
 
 	if key.KeyType != TypeOCT {
-		return nil, errors.New("jwk: invalid JWT, wrong type")
+		return nil, parseError("kty", ErrWrongKeyType)
 	}
 	if key.K == nil {
-		return nil, errors.New("jwk: invalid JWT, missing k")
+		return nil, parseError("k", ErrMissingMember)
 	}
 
 	return key, nil
 }
 
-// CryptoKey returns the underlying cryptographic key.
+// IsPrivate reports whether the key contains private key material. It
+// always returns true, as symmetric keys are secret.
+func (key *OctetSequenceKey) IsPrivate() bool {
+	return true
+}
+
+// CryptoKey returns the underlying cryptographic key, or nil if the key
+// has been destroyed.
 func (key *OctetSequenceKey) CryptoKey() CryptoKey {
+	if key.K == nil {
+		return nil
+	}
+
 	return key.K.Bytes()
 }
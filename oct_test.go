@@ -1,7 +1,6 @@
 package jwk
 
 import (
-	"bytes"
 	"encoding/json"
 	"testing"
 )
@@ -32,7 +31,12 @@ func TestOctetSequenceKey(t *testing.T) {
 		t.Error("excepted error on parsing invalid oct key (missing k)")
 	}
 
-	marshaled, err := json.Marshal(key)
+	_, err = json.Marshal(key)
+	if err == nil {
+		t.Error("excepted error on marshaling oct key without MarshalPrivate")
+	}
+
+	marshaled, err := MarshalPrivate(key)
 	if err != nil {
 		t.Error("failed to marshal valid oct key:", err)
 	}
@@ -42,7 +46,7 @@ func TestOctetSequenceKey(t *testing.T) {
 		t.Error("failed to unmarshal valid oct key:", err)
 	}
 
-	if !bytes.Equal(key.CryptoKey().([]byte), parsed.CryptoKey().([]byte)) {
-		t.Error("round-trip of oct key gave different raw keys")
+	if !EqualWithParams(key, parsed) {
+		t.Error("round-trip of oct key gave different keys")
 	}
 }
@@ -0,0 +1,190 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+
+	"github.com/ericyan/jwk/internal/base64url"
+)
+
+// CurveEd25519 is the Ed25519 subtype of octet key pairs, as defined in
+// RFC 8037, Section 2.
+const CurveEd25519 = "Ed25519"
+
+// Ed25519PublicKey represents an Ed25519 public key, which is an octet key
+// pair containing parameters defined in RFC 8037, Section 2.
+//
+// Ed25519PublicKey implements the Key interface.
+type Ed25519PublicKey struct {
+	*Params
+	CRV string           `json:"crv"`
+	X   *base64url.Value `json:"x"`
+
+	pub ed25519.PublicKey
+}
+
+// NewEd25519PublicKey creates a new Ed25519PublicKey.
+func NewEd25519PublicKey(pub ed25519.PublicKey, params *Params) (*Ed25519PublicKey, error) {
+	if params == nil {
+		params = &Params{KeyType: TypeOKP}
+	}
+	if params.KeyType == "" {
+		params.KeyType = TypeOKP
+	}
+	if params.KeyType != TypeOKP {
+		return nil, ErrWrongKeyType
+	}
+
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, ErrInvalidCryptoKey
+	}
+
+	return &Ed25519PublicKey{
+		params,
+		CurveEd25519,
+		base64url.NewValue(pub),
+		pub,
+	}, nil
+}
+
+// ParseEd25519PublicKey parses the JSON Web Key as an Ed25519 public key.
+func ParseEd25519PublicKey(jwk []byte) (*Ed25519PublicKey, error) {
+	key := new(Ed25519PublicKey)
+	err := unmarshalKey(jwk, key, "x")
+	if err != nil {
+		return nil, err
+	}
+
+	if key.KeyType != TypeOKP {
+		return nil, parseError("kty", ErrWrongKeyType)
+	}
+	if key.CRV != CurveEd25519 {
+		return nil, parseError("crv", ErrUnsupportedCurve)
+	}
+	if key.X == nil {
+		return nil, parseError("x", ErrMissingMember)
+	}
+	if len(key.X.Bytes()) != ed25519.PublicKeySize {
+		return nil, parseError("x", ErrInvalidMember)
+	}
+
+	key.pub = ed25519.PublicKey(key.X.Bytes())
+
+	return key, nil
+}
+
+// IsPrivate reports whether the key contains private key material.
+func (key *Ed25519PublicKey) IsPrivate() bool {
+	return false
+}
+
+// CryptoKey returns the underlying cryptographic key.
+func (key *Ed25519PublicKey) CryptoKey() CryptoKey {
+	return key.pub
+}
+
+// Ed25519PrivateKey represents an Ed25519 private key, which is an octet
+// key pair containing parameters defined in RFC 8037, Section 2.
+//
+// Ed25519PrivateKey implements the Key and crypto.Signer interfaces.
+type Ed25519PrivateKey struct {
+	*Ed25519PublicKey
+	D *base64url.Value `json:"d"`
+
+	priv ed25519.PrivateKey
+}
+
+// NewEd25519PrivateKey creates a new Ed25519PrivateKey.
+func NewEd25519PrivateKey(priv ed25519.PrivateKey, params *Params) (*Ed25519PrivateKey, error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidCryptoKey
+	}
+
+	pub, err := NewEd25519PublicKey(priv.Public().(ed25519.PublicKey), params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ed25519PrivateKey{
+		Ed25519PublicKey: pub,
+		D:                base64url.NewValue(priv.Seed()),
+		priv:             priv,
+	}, nil
+}
+
+// ParseEd25519PrivateKey parses the JSON Web Key as an Ed25519 private
+// key.
+func ParseEd25519PrivateKey(jwk []byte) (*Ed25519PrivateKey, error) {
+	key := new(Ed25519PrivateKey)
+	err := unmarshalKey(jwk, key, "x", "d")
+	if err != nil {
+		return nil, err
+	}
+
+	if key.D == nil {
+		return nil, parseError("d", ErrMissingMember)
+	}
+	if len(key.D.Bytes()) != ed25519.SeedSize {
+		return nil, parseError("d", ErrInvalidMember)
+	}
+
+	pub, err := ParseEd25519PublicKey(jwk)
+	if err != nil {
+		return nil, err
+	}
+	key.Ed25519PublicKey = pub
+
+	priv := ed25519.NewKeyFromSeed(key.D.Bytes())
+	if !bytes.Equal(priv.Public().(ed25519.PublicKey), pub.pub) {
+		return nil, parseError("d", fmt.Errorf("%w: does not match x", ErrInvalidMember))
+	}
+	key.priv = priv
+
+	return key, nil
+}
+
+// IsPrivate reports whether the key contains private key material.
+func (key *Ed25519PrivateKey) IsPrivate() bool {
+	return true
+}
+
+// CryptoKey returns the underlying cryptographic key, or nil if the key
+// has been destroyed.
+func (key *Ed25519PrivateKey) CryptoKey() CryptoKey {
+	if key.priv == nil {
+		return nil
+	}
+
+	return key.priv
+}
+
+// PublicKey returns the public counterpart of the private key. The
+// returned key does not share any state with the private key.
+func (key *Ed25519PrivateKey) PublicKey() Key {
+	pub := make(ed25519.PublicKey, ed25519.PublicKeySize)
+	copy(pub, key.pub)
+
+	return &Ed25519PublicKey{
+		key.Params.public(),
+		key.CRV,
+		base64url.NewValue(pub),
+		pub,
+	}
+}
+
+// Public returns the underlying Ed25519 public key.
+func (key *Ed25519PrivateKey) Public() crypto.PublicKey {
+	return key.pub
+}
+
+// Sign signs message with the private key, as in ed25519.PrivateKey.
+func (key *Ed25519PrivateKey) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if key.priv == nil {
+		return nil, ErrDestroyed
+	}
+
+	return key.priv.Sign(rand, message, opts)
+}
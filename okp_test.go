@@ -0,0 +1,129 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+var ed25519TestKey = ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+
+// Test vector from RFC 8037, Appendix A.1.
+const rfc8037PrivateKey = `{"kty":"OKP","crv":"Ed25519",
+  "d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+  "x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+
+func TestEd25519PublicKey(t *testing.T) {
+	pub := ed25519TestKey.Public().(ed25519.PublicKey)
+
+	key, err := NewEd25519PublicKey(pub, &Params{KeyID: "foo"})
+	if err != nil {
+		t.Fatal("failed to create valid Ed25519 public key:", err)
+	}
+
+	_, err = NewEd25519PublicKey(ed25519.PublicKey{}, nil)
+	if err == nil {
+		t.Error("excepted error on creating invalid Ed25519 public key (empty crypto key)")
+	}
+
+	_, err = NewEd25519PublicKey(pub, &Params{KeyType: "invalid"})
+	if err == nil {
+		t.Error("excepted error on creating invalid Ed25519 public key (invalid params)")
+	}
+
+	_, err = ParseEd25519PublicKey([]byte(`{"kty":"invalid"}`))
+	if err == nil {
+		t.Error("excepted error on parsing invalid Ed25519 public key (wrong type)")
+	}
+
+	_, err = ParseEd25519PublicKey([]byte(`{"kty":"OKP","crv":"X448","x":"AAAA"}`))
+	if err == nil {
+		t.Error("excepted error on parsing invalid Ed25519 public key (unsupported curve)")
+	}
+
+	_, err = ParseEd25519PublicKey([]byte(`{"kty":"OKP","crv":"Ed25519"}`))
+	if err == nil {
+		t.Error("excepted error on parsing invalid Ed25519 public key (missing x)")
+	}
+
+	marshaled, err := json.Marshal(key)
+	if err != nil {
+		t.Error("failed to marshal valid Ed25519 public key:", err)
+	}
+
+	parsed, err := ParseEd25519PublicKey(marshaled)
+	if err != nil {
+		t.Error("failed to unmarshal valid Ed25519 public key:", err)
+	}
+
+	if !EqualWithParams(key, parsed) {
+		t.Error("round-trip of Ed25519 public key gave different keys")
+	}
+}
+
+func TestEd25519PrivateKey(t *testing.T) {
+	key, err := NewEd25519PrivateKey(ed25519TestKey, &Params{KeyID: "foo"})
+	if err != nil {
+		t.Fatal("failed to create valid Ed25519 private key:", err)
+	}
+
+	_, err = NewEd25519PrivateKey(ed25519.PrivateKey{}, nil)
+	if err == nil {
+		t.Error("excepted error on creating invalid Ed25519 private key (empty crypto key)")
+	}
+
+	_, err = ParseEd25519PrivateKey([]byte(`{"kty":"OKP","crv":"Ed25519",
+	  "d":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+	  "x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`))
+	if err == nil {
+		t.Error("excepted error on parsing invalid Ed25519 private key (mismatched d)")
+	}
+
+	marshaled, err := MarshalPrivate(key)
+	if err != nil {
+		t.Error("failed to marshal valid Ed25519 private key:", err)
+	}
+
+	parsed, err := ParseEd25519PrivateKey(marshaled)
+	if err != nil {
+		t.Error("failed to unmarshal valid Ed25519 private key:", err)
+	}
+
+	if !EqualWithParams(key, parsed) {
+		t.Error("round-trip of Ed25519 private key gave different keys")
+	}
+}
+
+func TestEd25519PrivateKeyRFC8037(t *testing.T) {
+	key, err := Parse([]byte(rfc8037PrivateKey))
+	if err != nil {
+		t.Fatal("failed to parse RFC 8037 test key:", err)
+	}
+
+	priv, ok := key.(*Ed25519PrivateKey)
+	if !ok {
+		t.Fatalf("unexpected key type %T", key)
+	}
+
+	// Signature from RFC 8037, Appendix A.4.
+	sig, err := priv.Sign(rand.Reader, []byte("eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc"), crypto.Hash(0))
+	if err != nil {
+		t.Fatal("failed to sign:", err)
+	}
+
+	expected := "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+	if base64.RawURLEncoding.EncodeToString(sig) != expected {
+		t.Error("unexpected signature")
+	}
+
+	pub, ok := priv.PublicKey().(*Ed25519PublicKey)
+	if !ok {
+		t.Fatal("public key is not an Ed25519 public key")
+	}
+	if pub.IsPrivate() || !pub.pub.Equal(priv.Public()) {
+		t.Error("unexpected public key")
+	}
+}
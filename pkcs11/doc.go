@@ -0,0 +1,8 @@
+// Package pkcs11 exposes private keys held in a PKCS#11 token, such as a
+// hardware security module, as JSON Web Keys. The private key material
+// never leaves the token: each key is a jwk.SignerKey whose signing
+// operations are performed by the token.
+//
+// The package requires cgo and github.com/miekg/pkcs11 v1.1.2 or later.
+// It is empty when built without cgo.
+package pkcs11
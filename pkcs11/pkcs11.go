@@ -0,0 +1,437 @@
+//go:build cgo
+
+package pkcs11
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/ericyan/jwk"
+	p11 "github.com/miekg/pkcs11"
+)
+
+// Config specifies the token to open.
+type Config struct {
+	// Module is the path to the PKCS#11 module, such as
+	// /usr/lib/softhsm/libsofthsm2.so.
+	Module string
+
+	// TokenLabel is the label of the token to use.
+	TokenLabel string
+
+	// PIN is the user PIN of the token.
+	PIN string
+}
+
+// Provider enumerates the keys in a PKCS#11 token.
+//
+// Provider implements the jwk.Provider interface.
+type Provider struct {
+	ctx     *p11.Ctx
+	session p11.SessionHandle
+
+	mu sync.Mutex // guards the session, which is not safe for concurrent use
+}
+
+// Open opens a session with the token specified by cfg and logs in.
+func Open(cfg Config) (*Provider, error) {
+	ctx := p11.New(cfg.Module)
+	if ctx == nil {
+		return nil, fmt.Errorf("pkcs11: failed to load module '%s'", cfg.Module)
+	}
+
+	err := ctx.Initialize()
+	if err != nil {
+		ctx.Destroy()
+		return nil, err
+	}
+
+	p := &Provider{ctx: ctx}
+	err = p.open(cfg)
+	if err != nil {
+		ctx.Finalize()
+		ctx.Destroy()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// open opens a logged in session with the token labelled cfg.TokenLabel.
+func (p *Provider) open(cfg Config) error {
+	slots, err := p.ctx.GetSlotList(true)
+	if err != nil {
+		return err
+	}
+
+	for _, slot := range slots {
+		info, err := p.ctx.GetTokenInfo(slot)
+		if err != nil {
+			return err
+		}
+		if info.Label != cfg.TokenLabel {
+			continue
+		}
+
+		p.session, err = p.ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION)
+		if err != nil {
+			return err
+		}
+
+		err = p.ctx.Login(p.session, p11.CKU_USER, cfg.PIN)
+		if err != nil {
+			p.ctx.CloseSession(p.session)
+			return err
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("pkcs11: token '%s' not found", cfg.TokenLabel)
+}
+
+// Close logs out and releases the token.
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.ctx.Logout(p.session)
+	p.ctx.CloseSession(p.session)
+	err := p.ctx.Finalize()
+	p.ctx.Destroy()
+
+	return err
+}
+
+// FindKeys returns the private keys in the token with the given label and
+// ID. An empty label or a nil ID matches any key. The key ID parameter of
+// each key is the hex encoding of its PKCS#11 ID, or its label if the
+// key has no ID.
+func (p *Provider) FindKeys(label string, id []byte) ([]jwk.Key, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var keys []jwk.Key
+	for _, keyType := range []uint{p11.CKK_RSA, p11.CKK_EC} {
+		template := []*p11.Attribute{
+			p11.NewAttribute(p11.CKA_CLASS, p11.CKO_PRIVATE_KEY),
+			p11.NewAttribute(p11.CKA_KEY_TYPE, keyType),
+		}
+		if label != "" {
+			template = append(template, p11.NewAttribute(p11.CKA_LABEL, label))
+		}
+		if id != nil {
+			template = append(template, p11.NewAttribute(p11.CKA_ID, id))
+		}
+
+		handles, err := p.findObjects(template)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, handle := range handles {
+			key, err := p.newKey(handle, keyType)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// KeySet returns all private keys in the token.
+func (p *Provider) KeySet() (*jwk.Set, error) {
+	keys, err := p.FindKeys("", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jwk.Set{Keys: keys}, nil
+}
+
+// findObjects returns the handles of all objects matching template.
+func (p *Provider) findObjects(template []*p11.Attribute) ([]p11.ObjectHandle, error) {
+	err := p.ctx.FindObjectsInit(p.session, template)
+	if err != nil {
+		return nil, err
+	}
+	defer p.ctx.FindObjectsFinal(p.session)
+
+	var handles []p11.ObjectHandle
+	for {
+		found, _, err := p.ctx.FindObjects(p.session, 64)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return handles, nil
+		}
+		handles = append(handles, found...)
+	}
+}
+
+// attributes returns the values of the requested attributes of object.
+func (p *Provider) attributes(object p11.ObjectHandle, types ...uint) ([][]byte, error) {
+	template := make([]*p11.Attribute, len(types))
+	for i, typ := range types {
+		template[i] = p11.NewAttribute(typ, nil)
+	}
+
+	attrs, err := p.ctx.GetAttributeValue(p.session, object, template)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]byte, len(types))
+	for _, attr := range attrs {
+		for i, typ := range types {
+			if attr.Type == typ {
+				values[i] = attr.Value
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// newKey creates a JSON Web Key for the private key object of keyType.
+func (p *Provider) newKey(handle p11.ObjectHandle, keyType uint) (jwk.Key, error) {
+	attrs, err := p.attributes(handle, p11.CKA_LABEL, p11.CKA_ID)
+	if err != nil {
+		return nil, err
+	}
+	label, id := string(attrs[0]), attrs[1]
+
+	var pub crypto.PublicKey
+	if keyType == p11.CKK_RSA {
+		pub, err = p.rsaPublicKey(handle)
+	} else {
+		pub, err = p.ecdsaPublicKey(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kid := label
+	if len(id) > 0 {
+		kid = hex.EncodeToString(id)
+	}
+
+	return jwk.NewSignerKey(&signer{p, handle, pub}, &jwk.Params{KeyID: kid})
+}
+
+// rsaPublicKey returns the public key of the RSA private key object.
+func (p *Provider) rsaPublicKey(handle p11.ObjectHandle) (*rsa.PublicKey, error) {
+	attrs, err := p.attributes(handle, p11.CKA_MODULUS, p11.CKA_PUBLIC_EXPONENT)
+	if err != nil {
+		return nil, err
+	}
+	if len(attrs[0]) == 0 || len(attrs[1]) == 0 {
+		return nil, errors.New("pkcs11: missing RSA public key attributes")
+	}
+
+	e := new(big.Int).SetBytes(attrs[1])
+	if !e.IsInt64() || e.Int64() > 1<<31-1 {
+		return nil, errors.New("pkcs11: invalid RSA public exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(attrs[0]),
+		E: int(e.Int64()),
+	}, nil
+}
+
+// ecdsaPublicKey returns the public key from the EC public key object with
+// the given ID, as EC private key objects do not hold the public point.
+func (p *Provider) ecdsaPublicKey(id []byte) (*ecdsa.PublicKey, error) {
+	handles, err := p.findObjects([]*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_PUBLIC_KEY),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_EC),
+		p11.NewAttribute(p11.CKA_ID, id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(handles) != 1 {
+		return nil, errors.New("pkcs11: EC public key object not found")
+	}
+
+	attrs, err := p.attributes(handles[0], p11.CKA_EC_PARAMS, p11.CKA_EC_POINT)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseECPublicKey(attrs[0], attrs[1])
+}
+
+// Named curve OIDs defined in RFC 5480, Section 2.1.1.1.
+var (
+	oidNamedCurveP256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidNamedCurveP384 = asn1.ObjectIdentifier{1, 3, 132, 0, 34}
+	oidNamedCurveP521 = asn1.ObjectIdentifier{1, 3, 132, 0, 35}
+)
+
+// parseECPublicKey parses the DER-encoded CKA_EC_PARAMS and CKA_EC_POINT
+// attributes of an EC public key object.
+func parseECPublicKey(params, point []byte) (*ecdsa.PublicKey, error) {
+	var oid asn1.ObjectIdentifier
+	_, err := asn1.Unmarshal(params, &oid)
+	if err != nil {
+		return nil, errors.New("pkcs11: unsupported EC parameters")
+	}
+
+	var curve elliptic.Curve
+	var ecdhCurve ecdh.Curve
+	switch {
+	case oid.Equal(oidNamedCurveP256):
+		curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+	case oid.Equal(oidNamedCurveP384):
+		curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+	case oid.Equal(oidNamedCurveP521):
+		curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+	default:
+		return nil, errors.New("pkcs11: unsupported elliptic curve")
+	}
+
+	// CKA_EC_POINT is a DER-encoded OCTET STRING, although some modules
+	// return the raw point.
+	var raw []byte
+	rest, err := asn1.Unmarshal(point, &raw)
+	if err != nil || len(rest) > 0 {
+		raw = point
+	}
+
+	// Validate the point, which must be uncompressed.
+	_, err = ecdhCurve.NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: invalid EC point: %v", err)
+	}
+
+	size := (len(raw) - 1) / 2
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(raw[1 : 1+size]),
+		Y:     new(big.Int).SetBytes(raw[1+size:]),
+	}, nil
+}
+
+// signer is a crypto.Signer backed by a private key object.
+type signer struct {
+	p      *Provider
+	handle p11.ObjectHandle
+	pub    crypto.PublicKey
+}
+
+// Public returns the public key.
+func (s *signer) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign signs digest with the private key object. ECDSA signatures are
+// returned ASN.1-encoded, as in ecdsa.PrivateKey.
+func (s *signer) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	hash := opts.HashFunc()
+	if hash != 0 && len(digest) != hash.Size() {
+		return nil, errors.New("pkcs11: digest length does not match hash function")
+	}
+
+	var mech *p11.Mechanism
+	var data []byte
+	switch s.pub.(type) {
+	case *rsa.PublicKey:
+		if pssOpts, ok := opts.(*rsa.PSSOptions); ok {
+			params, err := pssParams(hash, pssOpts.SaltLength)
+			if err != nil {
+				return nil, err
+			}
+			mech, data = p11.NewMechanism(p11.CKM_RSA_PKCS_PSS, params), digest
+		} else {
+			prefix, ok := digestInfoPrefixes[hash]
+			if !ok {
+				return nil, errors.New("pkcs11: unsupported hash function")
+			}
+			mech, data = p11.NewMechanism(p11.CKM_RSA_PKCS, nil), append(prefix[:len(prefix):len(prefix)], digest...)
+		}
+	case *ecdsa.PublicKey:
+		mech, data = p11.NewMechanism(p11.CKM_ECDSA, nil), digest
+	default:
+		return nil, errors.New("pkcs11: unsupported key type")
+	}
+
+	s.p.mu.Lock()
+	defer s.p.mu.Unlock()
+
+	err := s.p.ctx.SignInit(s.p.session, []*p11.Mechanism{mech}, s.handle)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := s.p.ctx.Sign(s.p.session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.pub.(*ecdsa.PublicKey); ok {
+		return encodeECDSASignature(sig)
+	}
+
+	return sig, nil
+}
+
+// digestInfoPrefixes are the DER-encoded DigestInfo prefixes for
+// RSASSA-PKCS1-v1_5, as in RFC 8017, Section 9.2.
+var digestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// pssParams returns the CK_RSA_PKCS_PSS_PARAMS for hash and saltLength.
+func pssParams(hash crypto.Hash, saltLength int) ([]byte, error) {
+	var hashMech, mgf uint
+	switch hash {
+	case crypto.SHA256:
+		hashMech, mgf = p11.CKM_SHA256, p11.CKG_MGF1_SHA256
+	case crypto.SHA384:
+		hashMech, mgf = p11.CKM_SHA384, p11.CKG_MGF1_SHA384
+	case crypto.SHA512:
+		hashMech, mgf = p11.CKM_SHA512, p11.CKG_MGF1_SHA512
+	default:
+		return nil, errors.New("pkcs11: unsupported hash function")
+	}
+
+	switch saltLength {
+	case rsa.PSSSaltLengthAuto, rsa.PSSSaltLengthEqualsHash:
+		saltLength = hash.Size()
+	}
+	if saltLength < 0 {
+		return nil, errors.New("pkcs11: invalid PSS salt length")
+	}
+
+	return p11.NewPSSParams(hashMech, mgf, uint(saltLength)), nil
+}
+
+// encodeECDSASignature converts the r||s signature returned by CKM_ECDSA
+// to the ASN.1 encoding returned by crypto.Signer implementations.
+func encodeECDSASignature(sig []byte) ([]byte, error) {
+	if len(sig) == 0 || len(sig)%2 != 0 {
+		return nil, errors.New("pkcs11: invalid ECDSA signature")
+	}
+
+	size := len(sig) / 2
+	return asn1.Marshal(struct{ R, S *big.Int }{
+		new(big.Int).SetBytes(sig[:size]),
+		new(big.Int).SetBytes(sig[size:]),
+	})
+}
@@ -0,0 +1,114 @@
+//go:build cgo
+
+package pkcs11
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"os"
+	"testing"
+)
+
+func TestParseECPublicKey(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	ecdhPub, _ := priv.PublicKey.ECDH()
+	params, _ := asn1.Marshal(oidNamedCurveP384)
+	point, _ := asn1.Marshal(ecdhPub.Bytes())
+
+	for _, p := range [][]byte{point, ecdhPub.Bytes()} {
+		pub, err := parseECPublicKey(params, p)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if !priv.PublicKey.Equal(pub) {
+			t.Error("public key mismatch")
+		}
+	}
+
+	_, err := parseECPublicKey(params, []byte{4, 1, 2, 3})
+	if err == nil {
+		t.Error("expected error on invalid point")
+	}
+
+	params, _ = asn1.Marshal(asn1.ObjectIdentifier{1, 3, 132, 0, 10}) // secp256k1
+	_, err = parseECPublicKey(params, point)
+	if err == nil {
+		t.Error("expected error on unsupported curve")
+	}
+}
+
+func TestEncodeECDSASignature(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	digest := sha256.Sum256([]byte("foo"))
+	r, s, _ := ecdsa.Sign(rand.Reader, priv, digest[:])
+
+	raw := make([]byte, 64)
+	r.FillBytes(raw[:32])
+	s.FillBytes(raw[32:])
+
+	der, err := encodeECDSASignature(raw)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if !ecdsa.VerifyASN1(&priv.PublicKey, digest[:], der) {
+		t.Error("invalid signature")
+	}
+}
+
+// TestSoftHSM runs against a token set up with, for example:
+//
+//	softhsm2-util --init-token --free --label jwk --pin 1234 --so-pin 1234
+//	pkcs11-tool --module $PKCS11_MODULE --login --pin 1234 \
+//		--keypairgen --key-type EC:prime256v1 --id 01 --label signing
+func TestSoftHSM(t *testing.T) {
+	module := os.Getenv("PKCS11_MODULE")
+	if module == "" {
+		t.Skip("PKCS11_MODULE not set")
+	}
+
+	p, err := Open(Config{
+		Module:     module,
+		TokenLabel: os.Getenv("PKCS11_TOKEN_LABEL"),
+		PIN:        os.Getenv("PKCS11_PIN"),
+	})
+	if err != nil {
+		t.Fatal("failed to open token:", err)
+	}
+	defer p.Close()
+
+	set, err := p.KeySet()
+	if err != nil {
+		t.Fatal("failed to enumerate keys:", err)
+	}
+	if len(set.Keys) == 0 {
+		t.Skip("no keys in token")
+	}
+
+	digest := sha256.Sum256([]byte("foo"))
+	for _, key := range set.Keys {
+		signer := key.(crypto.Signer)
+		sig, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		if err != nil {
+			t.Fatalf("failed to sign with key '%s': %v", key.ID(), err)
+		}
+
+		if pub, ok := signer.Public().(*ecdsa.PublicKey); ok && !ecdsa.VerifyASN1(pub, digest[:], sig) {
+			t.Errorf("invalid signature from key '%s'", key.ID())
+		}
+	}
+
+	pub := set.Public()
+	if len(pub.Keys) != len(set.Keys) {
+		t.Fatal("public set length mismatch")
+	}
+	for i, key := range pub.Keys {
+		want, _ := set.Keys[i].(crypto.Signer).Public().(interface{ Equal(crypto.PublicKey) bool })
+		if key.IsPrivate() || want == nil || !want.Equal(key.CryptoKey()) {
+			t.Errorf("unexpected public key '%s'", key.ID())
+		}
+	}
+}
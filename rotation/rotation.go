@@ -0,0 +1,278 @@
+// Package rotation manages signing keys through their lifecycle.
+//
+// A key is published as pending before it is used, so that verifiers
+// have a chance to fetch it; it then becomes the active signing key;
+// when replaced, it remains published as retiring so that outstanding
+// signatures can still be verified; and finally it is retired and no
+// longer published.
+package rotation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ericyan/jwk"
+)
+
+// State is the lifecycle state of a key.
+type State int
+
+// Lifecycle states, in order.
+const (
+	Pending State = iota
+	Active
+	Retiring
+	Retired
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Active:
+		return "active"
+	case Retiring:
+		return "retiring"
+	case Retired:
+		return "retired"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrNoActiveKey is returned when there is no active signing key.
+var ErrNoActiveKey = errors.New("rotation: no active key")
+
+// Entry records a key and the times at which it entered each state. The
+// time of a state not yet reached is zero.
+type Entry struct {
+	Key       jwk.Key
+	State     State
+	Published time.Time
+	Activated time.Time
+	Retiring  time.Time
+	Retired   time.Time
+}
+
+// Policy controls the timing of state transitions.
+type Policy struct {
+	// PrePublish is how long a key is published as pending before it may
+	// become active. It should exceed the time verifiers cache key sets.
+	PrePublish time.Duration
+
+	// Lifetime is how long a key stays active before it is replaced.
+	Lifetime time.Duration
+
+	// Overlap is how long a replaced key stays published as retiring. It
+	// should exceed the validity of anything signed with the key.
+	Overlap time.Duration
+}
+
+// Manager tracks keys through their lifecycle. It is safe for concurrent
+// use.
+//
+// Manager implements the jwk.Provider interface, publishing the pending,
+// active and retiring keys.
+type Manager struct {
+	Policy Policy
+
+	// Generate creates new keys. If nil, keys must be added with Add.
+	Generate func() (jwk.Key, error)
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+
+	mu      sync.RWMutex
+	entries []*Entry
+}
+
+// NewManager creates a new Manager.
+func NewManager(policy Policy, generate func() (jwk.Key, error)) *Manager {
+	return &Manager{Policy: policy, Generate: generate}
+}
+
+func (m *Manager) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+
+	return time.Now()
+}
+
+// Add adds a private key in the given state, such as a key restored
+// from storage. The key must have a unique key ID, and there can be at
+// most one active key.
+func (m *Manager) Add(key jwk.Key, state State) error {
+	if !key.IsPrivate() {
+		return errors.New("rotation: not a private key")
+	}
+	if key.ID() == "" {
+		return errors.New("rotation: missing key ID")
+	}
+	if state < Pending || state > Retired {
+		return errors.New("rotation: invalid state")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range m.entries {
+		if e.Key.ID() == key.ID() {
+			return errors.New("rotation: duplicate key ID")
+		}
+		if state == Active && e.State == Active {
+			return errors.New("rotation: there is already an active key")
+		}
+	}
+
+	now := m.now()
+	e := &Entry{Key: key, State: state, Published: now}
+	if state >= Active {
+		e.Activated = now
+	}
+	if state >= Retiring {
+		e.Retiring = now
+	}
+	if state == Retired {
+		e.Retired = now
+	}
+	m.entries = append(m.entries, e)
+
+	return nil
+}
+
+// Advance performs the state transitions that are due:
+//
+//   - retiring keys are retired, and destroyed if they implement
+//     jwk.Destroyer, once the overlap has elapsed;
+//   - a pending key is generated, if there is none, once the active key
+//     is within PrePublish of the end of its lifetime;
+//   - the oldest pending key that has been published for at least
+//     PrePublish replaces the active key at the end of its lifetime.
+//
+// If there is no active key at all, the oldest pending key is activated
+// immediately, generating one first if needed.
+func (m *Manager) Advance() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	p := m.Policy
+
+	for _, e := range m.entries {
+		if e.State == Retiring && !now.Before(e.Retiring.Add(p.Overlap)) {
+			e.State = Retired
+			e.Retired = now
+			if d, ok := e.Key.(jwk.Destroyer); ok {
+				d.Destroy()
+			}
+		}
+	}
+
+	active, pending := m.find(Active), m.find(Pending)
+
+	if pending == nil && m.Generate != nil &&
+		(active == nil || !now.Before(active.Activated.Add(p.Lifetime-p.PrePublish))) {
+		key, err := m.Generate()
+		if err != nil {
+			return err
+		}
+		if !key.IsPrivate() || key.ID() == "" {
+			return errors.New("rotation: generated key must be private and have a key ID")
+		}
+
+		pending = &Entry{Key: key, State: Pending, Published: now}
+		m.entries = append(m.entries, pending)
+	}
+
+	switch {
+	case pending == nil:
+	case active == nil:
+		activate(pending, now)
+	case !now.Before(active.Activated.Add(p.Lifetime)) &&
+		!now.Before(pending.Published.Add(p.PrePublish)):
+		active.State = Retiring
+		active.Retiring = now
+		activate(pending, now)
+	}
+
+	return nil
+}
+
+func activate(e *Entry, now time.Time) {
+	e.State = Active
+	e.Activated = now
+}
+
+// find returns the oldest entry in the given state.
+func (m *Manager) find(state State) *Entry {
+	for _, e := range m.entries {
+		if e.State == state {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// SigningKey returns the active key.
+func (m *Manager) SigningKey() (jwk.Key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if e := m.find(Active); e != nil {
+		return e.Key, nil
+	}
+
+	return nil, ErrNoActiveKey
+}
+
+// KeySet returns the public keys of pending, active and retiring keys.
+func (m *Manager) KeySet() (*jwk.Set, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := &jwk.Set{Keys: []jwk.Key{}}
+	for _, e := range m.entries {
+		if e.State != Retired {
+			set.Keys = append(set.Keys, e.Key)
+		}
+	}
+
+	return set.Public(), nil
+}
+
+// Entries returns a snapshot of all keys, including retired ones.
+func (m *Manager) Entries() []Entry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := make([]Entry, len(m.entries))
+	for i, e := range m.entries {
+		entries[i] = *e
+	}
+
+	return entries
+}
+
+// Run calls Advance immediately and then at every interval, until ctx
+// is done or Advance fails.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Advance(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package rotation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ericyan/jwk"
+)
+
+type testClock struct {
+	t time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{time.Unix(1700000000, 0)}
+}
+
+func (c *testClock) Now() time.Time {
+	return c.t
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func testPolicy() Policy {
+	return Policy{PrePublish: time.Hour, Lifetime: 24 * time.Hour, Overlap: 2 * time.Hour}
+}
+
+func entryOf(m *Manager, kid string) Entry {
+	for _, e := range m.Entries() {
+		if e.Key.ID() == kid {
+			return e
+		}
+	}
+	return Entry{State: -1}
+}
+
+func stateOf(m *Manager, kid string) State {
+	return entryOf(m, kid).State
+}
+
+func testGenerator() func() (jwk.Key, error) {
+	n := 0
+	return func() (jwk.Key, error) {
+		n++
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return jwk.New(priv, &jwk.Params{KeyID: fmt.Sprintf("key-%d", n)})
+	}
+}
+
+func keyIDs(m *Manager) []string {
+	set, _ := m.KeySet()
+	ids := make([]string, len(set.Keys))
+	for i, key := range set.Keys {
+		ids[i] = key.ID()
+	}
+	return ids
+}
+
+func TestManagerLifecycle(t *testing.T) {
+	clock := newTestClock()
+	m := NewManager(testPolicy(), testGenerator())
+	m.Now = clock.Now
+
+	if _, err := m.SigningKey(); err != ErrNoActiveKey {
+		t.Error("expected ErrNoActiveKey before first advance")
+	}
+
+	// Bootstrap: the first key is activated immediately.
+	if err := m.Advance(); err != nil {
+		t.Fatal(err)
+	}
+	key, err := m.SigningKey()
+	if err != nil || key.ID() != "key-1" {
+		t.Fatal("unexpected signing key after bootstrap")
+	}
+
+	// The next key is published PrePublish before the end of the lifetime.
+	clock.Add(22 * time.Hour)
+	m.Advance()
+	if len(keyIDs(m)) != 1 {
+		t.Error("next key published too early")
+	}
+
+	clock.Add(time.Hour)
+	m.Advance()
+	if ids := keyIDs(m); len(ids) != 2 || stateOf(m, "key-2") != Pending {
+		t.Errorf("next key not published: %v", ids)
+	}
+	if key, _ := m.SigningKey(); key.ID() != "key-1" {
+		t.Error("pending key used for signing")
+	}
+
+	// At the end of the lifetime the keys are swapped.
+	clock.Add(time.Hour)
+	m.Advance()
+	if key, _ := m.SigningKey(); key.ID() != "key-2" {
+		t.Error("active key not rotated")
+	}
+	if stateOf(m, "key-1") != Retiring || len(keyIDs(m)) != 2 {
+		t.Error("old key not published as retiring")
+	}
+
+	// The retiring key is retired after the overlap.
+	clock.Add(2 * time.Hour)
+	m.Advance()
+	if e := entryOf(m, "key-1"); e.State != Retired || !e.Retired.Equal(clock.Now()) {
+		t.Error("old key not retired:", e)
+	}
+	for _, b := range entryOf(m, "key-1").Key.(*jwk.ECDSAPrivateKey).D.Bytes() {
+		if b != 0 {
+			t.Fatal("retired key not destroyed")
+		}
+	}
+	if ids := keyIDs(m); len(ids) != 1 || ids[0] != "key-2" {
+		t.Errorf("unexpected published keys: %v", ids)
+	}
+
+	e := entryOf(m, "key-2")
+	if e.Published.IsZero() || e.Activated.Sub(e.Published) != time.Hour || !e.Retiring.IsZero() {
+		t.Error("unexpected timestamps:", e)
+	}
+}
+
+func TestManagerPrePublish(t *testing.T) {
+	clock := newTestClock()
+	m := &Manager{Policy: testPolicy(), Now: clock.Now}
+
+	a, _ := testGenerator()()
+	if err := m.Add(a, Active); err != nil {
+		t.Fatal(err)
+	}
+
+	// A key added late is not activated before it has been published for
+	// PrePublish, even if the active key is overdue.
+	clock.Add(24 * time.Hour)
+	b, _ := jwk.New(mustGenerate(t), &jwk.Params{KeyID: "late"})
+	if err := m.Add(b, Pending); err != nil {
+		t.Fatal(err)
+	}
+	m.Advance()
+	if key, _ := m.SigningKey(); key.ID() != a.ID() {
+		t.Error("pending key activated before PrePublish")
+	}
+
+	clock.Add(time.Hour)
+	m.Advance()
+	if key, _ := m.SigningKey(); key.ID() != "late" {
+		t.Error("pending key not activated after PrePublish")
+	}
+}
+
+func mustGenerate(t *testing.T) *ecdsa.PrivateKey {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv
+}
+
+func TestManagerAdd(t *testing.T) {
+	m := new(Manager)
+
+	priv, _ := jwk.New(mustGenerate(t), &jwk.Params{KeyID: "a"})
+	if err := m.Add(priv, Active); err != nil {
+		t.Fatal(err)
+	}
+
+	other, _ := jwk.New(mustGenerate(t), &jwk.Params{KeyID: "b"})
+	noKID, _ := jwk.New(mustGenerate(t), nil)
+	pub, _ := jwk.New(&mustGenerate(t).PublicKey, &jwk.Params{KeyID: "c"})
+
+	cases := []struct {
+		key   jwk.Key
+		state State
+	}{
+		{priv, Pending},   // duplicate key ID
+		{other, Active},   // second active key
+		{other, State(9)}, // invalid state
+		{noKID, Pending},  // missing key ID
+		{pub, Pending},    // not a private key
+	}
+	for i, c := range cases {
+		if err := m.Add(c.key, c.state); err == nil {
+			t.Errorf("case %d: expected error on adding key", i)
+		}
+	}
+
+	set, _ := m.KeySet()
+	if len(set.Keys) != 1 || set.Keys[0].IsPrivate() {
+		t.Error("key set must contain only public keys")
+	}
+}
+
+func TestManagerGenerateError(t *testing.T) {
+	m := NewManager(testPolicy(), func() (jwk.Key, error) {
+		return nil, errors.New("boom")
+	})
+
+	if err := m.Run(context.Background(), time.Hour); err == nil {
+		t.Error("expected Run to return generator error")
+	}
+}
+
+func TestManagerRun(t *testing.T) {
+	m := NewManager(testPolicy(), testGenerator())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Run(ctx, time.Hour); err != context.Canceled {
+		t.Error("unexpected error:", err)
+	}
+	if _, err := m.SigningKey(); err != nil {
+		t.Error("Run did not advance:", err)
+	}
+}
+
+func TestStateString(t *testing.T) {
+	for s, name := range map[State]string{Pending: "pending", Active: "active", Retiring: "retiring", Retired: "retired", 9: "unknown"} {
+		if s.String() != name {
+			t.Errorf("unexpected name for state %d: %s", s, s.String())
+		}
+	}
+}
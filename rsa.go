@@ -1,9 +1,11 @@
 package jwk
 
 import (
+	"crypto"
 	"crypto/rsa"
 	"encoding/json"
-	"errors"
+	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/ericyan/jwk/internal/base64url"
@@ -30,12 +32,12 @@ func NewRSAPublicKey(pub *rsa.PublicKey, params *Params) (*RSAPublicKey, error)
 		params.KeyType = TypeRSA
 	}
 	if params.KeyType != TypeRSA {
-		return nil, errors.New("jwk: invalid params, wrong key type")
+		return nil, ErrWrongKeyType
 	}
 
 	// Sanity checks for the public key
 	if pub.N == nil || pub.E < 2 || pub.E > 1<<31-1 {
-		return nil, errors.New("jwk: invalid crypto key")
+		return nil, ErrInvalidCryptoKey
 	}
 
 	return &RSAPublicKey{
@@ -49,19 +51,19 @@ func NewRSAPublicKey(pub *rsa.PublicKey, params *Params) (*RSAPublicKey, error)
 // ParseRSAPublicKey parses the JSON Web Key as an RSA public key.
 func ParseRSAPublicKey(jwk []byte) (*RSAPublicKey, error) {
 	key := new(RSAPublicKey)
-	err := json.Unmarshal(jwk, key)
+	err := unmarshalKey(jwk, key, "n", "e")
 	if err != nil {
 		return nil, err
 	}
 
 	if key.KeyType != TypeRSA {
-		return nil, errors.New("jwk: invalid JWT, wrong type")
+		return nil, parseError("kty", ErrWrongKeyType)
 	}
 	if key.N == nil {
-		return nil, errors.New("jwk: invalid JWT, missing N")
+		return nil, parseError("n", ErrMissingMember)
 	}
 	if key.E == nil {
-		return nil, errors.New("jwk: invalid JWT, missing E")
+		return nil, parseError("e", ErrMissingMember)
 	}
 
 	key.pub = &rsa.PublicKey{
@@ -72,6 +74,11 @@ func ParseRSAPublicKey(jwk []byte) (*RSAPublicKey, error) {
 	return key, nil
 }
 
+// IsPrivate reports whether the key contains private key material.
+func (key *RSAPublicKey) IsPrivate() bool {
+	return false
+}
+
 // CryptoKey returns the underlying cryptographic key.
 func (key *RSAPublicKey) CryptoKey() CryptoKey {
 	return key.pub
@@ -80,7 +87,8 @@ func (key *RSAPublicKey) CryptoKey() CryptoKey {
 // RSAPrivateKey represents an RSA private key, which contains
 // algorithm-specific parameters defined in RFC7518, Section 6.3.2.
 //
-// RSAPrivate implements the Key interface.
+// RSAPrivateKey implements the Key, crypto.Signer and crypto.Decrypter
+// interfaces.
 type RSAPrivateKey struct {
 	*RSAPublicKey
 	D   *base64url.Value `json:"d"`
@@ -97,10 +105,10 @@ type RSAPrivateKey struct {
 // NewRSAPrivateKey creates a new RSAPrivate.
 func NewRSAPrivateKey(priv *rsa.PrivateKey, params *Params) (*RSAPrivateKey, error) {
 	if priv == nil || priv.Validate() != nil {
-		return nil, errors.New("jwk: invalid crypto key")
+		return nil, ErrInvalidCryptoKey
 	}
 	if len(priv.Primes) > 2 {
-		return nil, errors.New("jwk: unsupported key: multi-prime RSA key")
+		return nil, fmt.Errorf("%w: multi-prime RSA key", ErrUnsupportedKeyType)
 	}
 
 	pub, err := NewRSAPublicKey(&priv.PublicKey, params)
@@ -131,19 +139,19 @@ func NewRSAPrivateKey(priv *rsa.PrivateKey, params *Params) (*RSAPrivateKey, err
 // ParseRSAPrivateKey parses the JSON Web Key as an RSA private key.
 func ParseRSAPrivateKey(jwk []byte) (*RSAPrivateKey, error) {
 	key := new(RSAPrivateKey)
-	err := json.Unmarshal(jwk, key)
+	err := unmarshalKey(jwk, key, "n", "e", "d", "p", "q", "dp", "dq", "qi")
 	if err != nil {
 		return nil, err
 	}
 
 	if key.D == nil {
-		return nil, errors.New("jwk: invalid JWT, missing D")
+		return nil, parseError("d", ErrMissingMember)
 	}
 	if key.P == nil {
-		return nil, errors.New("jwk: invalid JWT, missing P")
+		return nil, parseError("p", ErrMissingMember)
 	}
 	if key.Q == nil {
-		return nil, errors.New("jwk: invalid JWT, missing Q")
+		return nil, parseError("q", ErrMissingMember)
 	}
 
 	pub, err := ParseRSAPublicKey(jwk)
@@ -171,14 +179,63 @@ func ParseRSAPrivateKey(jwk []byte) (*RSAPrivateKey, error) {
 	}
 
 	if err := priv.Validate(); err != nil {
-		return nil, err
+		return nil, parseError("d", fmt.Errorf("%w: %w", ErrInvalidMember, err))
 	}
 	key.priv = priv
 
 	return key, nil
 }
 
-// CryptoKey returns the underlying cryptographic key.
+// IsPrivate reports whether the key contains private key material.
+func (key *RSAPrivateKey) IsPrivate() bool {
+	return true
+}
+
+// CryptoKey returns the underlying cryptographic key, or nil if the key
+// has been destroyed.
 func (key *RSAPrivateKey) CryptoKey() CryptoKey {
+	if key.priv == nil {
+		return nil
+	}
+
 	return key.priv
 }
+
+// PublicKey returns the public counterpart of the private key. The
+// returned key does not share any state with the private key.
+func (key *RSAPrivateKey) PublicKey() Key {
+	pub := &rsa.PublicKey{
+		N: new(big.Int).Set(key.pub.N),
+		E: key.pub.E,
+	}
+
+	return &RSAPublicKey{
+		key.Params.public(),
+		base64url.NewBigInt(pub.N),
+		base64url.NewUint64(uint64(pub.E)),
+		pub,
+	}
+}
+
+// Public returns the underlying RSA public key.
+func (key *RSAPrivateKey) Public() crypto.PublicKey {
+	return key.pub
+}
+
+// Sign signs digest with the private key, as in rsa.PrivateKey.
+func (key *RSAPrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if key.priv == nil {
+		return nil, ErrDestroyed
+	}
+
+	return key.priv.Sign(rand, digest, opts)
+}
+
+// Decrypt decrypts msg with the private key, as in rsa.PrivateKey.
+func (key *RSAPrivateKey) Decrypt(rand io.Reader, msg []byte, opts crypto.DecrypterOpts) ([]byte, error) {
+	if key.priv == nil {
+		return nil, ErrDestroyed
+	}
+
+	return key.priv.Decrypt(rand, msg, opts)
+}
@@ -1,9 +1,12 @@
 package jwk
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"encoding/json"
+	"reflect"
 	"testing"
 )
 
@@ -45,9 +48,8 @@ func TestRSAPublicKey(t *testing.T) {
 		t.Error("failed to unmarshal valid RSA public key:", err)
 	}
 
-	if key.CryptoKey().(*rsa.PublicKey).N.Cmp(parsed.CryptoKey().(*rsa.PublicKey).N) != 0 &&
-		key.CryptoKey().(*rsa.PublicKey).E != parsed.CryptoKey().(*rsa.PublicKey).E {
-		t.Error("round-trip of RSA public key gave different raw keys")
+	if !EqualWithParams(key, parsed) {
+		t.Error("round-trip of RSA public key gave different keys")
 	}
 }
 
@@ -77,7 +79,7 @@ func TestRSAPrivateKey(t *testing.T) {
 		t.Error("excepted error on parsing invalid RSA public key key (missing n, d, q)")
 	}
 
-	marshaled, err := json.Marshal(key)
+	marshaled, err := MarshalPrivate(key)
 	if err != nil {
 		t.Error("failed to marshal valid RSA private key:", err)
 	}
@@ -87,10 +89,54 @@ func TestRSAPrivateKey(t *testing.T) {
 		t.Error("failed to unmarshal valid RSA private key:", err)
 	}
 
-	orgPriv := key.CryptoKey().(*rsa.PrivateKey)
-	parsedPriv := parsed.CryptoKey().(*rsa.PrivateKey)
-	if orgPriv.PublicKey.N.Cmp(parsedPriv.PublicKey.N) != 0 && orgPriv.PublicKey.E != parsedPriv.PublicKey.E &&
-		orgPriv.D.Cmp(parsedPriv.D) != 0 && orgPriv.Primes[0].Cmp(parsedPriv.Primes[0]) != 0 && orgPriv.Primes[1].Cmp(parsedPriv.Primes[1]) != 0 {
-		t.Error("round-trip of RSA private key gave different raw keys")
+	if !EqualWithParams(key, parsed) {
+		t.Error("round-trip of RSA private key gave different keys")
+	}
+}
+
+func TestRSAPrivateKeyPublic(t *testing.T) {
+	key, err := NewRSAPrivateKey(rsaTestKey, &Params{KeyID: "foo", KeyOps: []string{"sign", "decrypt", "unwrapKey"}})
+	if err != nil {
+		t.Fatal("failed to create valid RSA private key:", err)
+	}
+
+	pub, ok := key.PublicKey().(*RSAPublicKey)
+	if !ok {
+		t.Fatal("public key is not an RSA public key")
+	}
+	if pub.ID() != "foo" || !reflect.DeepEqual(pub.KeyOps, []string{"verify", "encrypt", "wrapKey"}) {
+		t.Error("unexpected public key params:", pub.Params)
+	}
+	if pub.Params == key.Params {
+		t.Error("public key shares params with private key")
+	}
+
+	if pub.CryptoKey().(*rsa.PublicKey).N == rsaTestKey.N {
+		t.Error("public key shares crypto key with private key")
+	}
+}
+
+func TestRSAPrivateKeySignerDecrypter(t *testing.T) {
+	key, err := NewRSAPrivateKey(rsaTestKey, nil)
+	if err != nil {
+		t.Fatal("failed to create valid RSA private key:", err)
+	}
+
+	digest := sha256.Sum256([]byte("foo"))
+	sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		t.Fatal("failed to sign with RSA private key:", err)
+	}
+	if rsa.VerifyPKCS1v15(key.Public().(*rsa.PublicKey), crypto.SHA256, digest[:], sig) != nil {
+		t.Error("invalid signature")
+	}
+
+	ciphertext, _ := rsa.EncryptOAEP(sha256.New(), rand.Reader, &rsaTestKey.PublicKey, []byte("foo"), nil)
+	plaintext, err := key.Decrypt(rand.Reader, ciphertext, &rsa.OAEPOptions{Hash: crypto.SHA256})
+	if err != nil {
+		t.Fatal("failed to decrypt with RSA private key:", err)
+	}
+	if string(plaintext) != "foo" {
+		t.Error("plaintext mismatch")
 	}
 }
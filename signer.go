@@ -0,0 +1,122 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"math/big"
+)
+
+// SignerKey represents a private key whose key material is not
+// extractable, such as a key held in a hardware security module or a
+// cloud key management service. Only the public members are exposed and
+// signing is delegated to the underlying crypto.Signer.
+//
+// SignerKey implements the Key and crypto.Signer interfaces.
+type SignerKey struct {
+	pub    Key
+	signer crypto.Signer
+}
+
+// NewSignerKey creates a new SignerKey. The public key of signer must be
+// of a supported type.
+func NewSignerKey(signer crypto.Signer, params *Params) (*SignerKey, error) {
+	if signer == nil {
+		return nil, ErrInvalidCryptoKey
+	}
+
+	pub, err := New(signer.Public(), params)
+	if err != nil {
+		return nil, err
+	}
+	if pub.IsPrivate() {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	return &SignerKey{pub, signer}, nil
+}
+
+// copyPublicKey returns a deep copy of pub, which must be of a type
+// supported by New.
+func copyPublicKey(pub crypto.PublicKey) crypto.PublicKey {
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		return &ecdsa.PublicKey{
+			Curve: k.Curve,
+			X:     new(big.Int).Set(k.X),
+			Y:     new(big.Int).Set(k.Y),
+		}
+	case *rsa.PublicKey:
+		return &rsa.PublicKey{
+			N: new(big.Int).Set(k.N),
+			E: k.E,
+		}
+	case ed25519.PublicKey:
+		return append(ed25519.PublicKey(nil), k...)
+	default:
+		return pub
+	}
+}
+
+// ID returns the key ID parameter.
+func (key *SignerKey) ID() string {
+	return key.pub.ID()
+}
+
+// Type returns the key type parameter.
+func (key *SignerKey) Type() string {
+	return key.pub.Type()
+}
+
+// Alg returns the algorithm parameter.
+func (key *SignerKey) Alg() string {
+	return key.pub.Alg()
+}
+
+// Parameters returns the common parameters.
+func (key *SignerKey) Parameters() *Params {
+	return key.pub.Parameters()
+}
+
+// IsPrivate reports whether the key contains private key material. It
+// always returns true, even though the private key material is not
+// accessible.
+func (key *SignerKey) IsPrivate() bool {
+	return true
+}
+
+// CryptoKey returns the underlying crypto.Signer.
+func (key *SignerKey) CryptoKey() CryptoKey {
+	return key.signer
+}
+
+// PublicKey returns the public counterpart of the private key. The
+// returned key does not share any state with the private key.
+func (key *SignerKey) PublicKey() Key {
+	// New cannot fail, as it already accepted the public key in
+	// NewSignerKey and sets the key type itself.
+	params := key.pub.Parameters().public()
+	params.KeyType = ""
+	pub, _ := New(copyPublicKey(key.pub.CryptoKey()), params)
+
+	return pub
+}
+
+// Public returns the public key of the underlying crypto.Signer.
+func (key *SignerKey) Public() crypto.PublicKey {
+	return key.signer.Public()
+}
+
+// Sign signs digest using the underlying crypto.Signer.
+func (key *SignerKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return key.signer.Sign(rand, digest, opts)
+}
+
+// MarshalJSON implements the json.Marshaler interface. Only the public
+// members are serialized.
+func (key *SignerKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(key.pub)
+}
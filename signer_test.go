@@ -0,0 +1,96 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+// opaqueSigner is a crypto.Signer that does not expose its private key.
+type opaqueSigner struct {
+	priv *ecdsa.PrivateKey
+}
+
+func (s *opaqueSigner) Public() crypto.PublicKey {
+	return s.priv.Public()
+}
+
+func (s *opaqueSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.priv.Sign(rand, digest, opts)
+}
+
+func TestSignerKey(t *testing.T) {
+	signer := &opaqueSigner{ecdsaTestKeyP256}
+	k, err := New(signer, &Params{KeyID: "hsm", KeyOps: []string{"sign"}})
+	if err != nil {
+		t.Fatal("failed to create signer key:", err)
+	}
+	key, ok := k.(*SignerKey)
+	if !ok {
+		t.Fatal("unexpected key type")
+	}
+
+	if key.ID() != "hsm" || key.Type() != TypeEC || !key.IsPrivate() {
+		t.Error("unexpected key params")
+	}
+
+	digest := sha256.Sum256([]byte("foo"))
+	sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		t.Fatal("failed to sign:", err)
+	}
+	if !ecdsa.VerifyASN1(&ecdsaTestKeyP256.PublicKey, digest[:], sig) {
+		t.Error("invalid signature")
+	}
+
+	marshaled, err := json.Marshal(key)
+	if err != nil {
+		t.Fatal("failed to marshal signer key:", err)
+	}
+	parsed, err := Parse(marshaled)
+	if err != nil {
+		t.Fatal("failed to parse signer key:", err)
+	}
+	if parsed.IsPrivate() {
+		t.Error("private key material serialized")
+	}
+
+	pub := key.PublicKey()
+	if !Equal(pub, parsed) || pub.Parameters().KeyOps[0] != "verify" {
+		t.Error("unexpected public key")
+	}
+	if pub.Parameters() == key.Parameters() || key.Parameters().KeyOps[0] != "sign" {
+		t.Error("public key shares params with signer key")
+	}
+	pub.CryptoKey().(*ecdsa.PublicKey).X.SetInt64(0)
+	if ecdsaTestKeyP256.X.Sign() == 0 {
+		t.Error("public key shares crypto key with signer")
+	}
+
+	priv, _ := NewECDSAPrivateKey(ecdsaTestKeyP256, nil)
+	if !Equal(key, priv) {
+		t.Error("signer key not equal to private key with same public key")
+	}
+
+	set := (&Set{Keys: []Key{key}}).Public()
+	if len(set.Keys) != 1 || set.Keys[0].IsPrivate() {
+		t.Error("unexpected public set")
+	}
+
+	// Params changed after construction apply to the public key too.
+	key.Parameters().Algorithm = "ES256"
+	key.Parameters().X509SHA256Thumbprint = "foo"
+	pub = (&Set{Keys: []Key{key}}).Public().Keys[0]
+	if pub.Alg() != "ES256" || pub.Parameters().X509SHA256Thumbprint != "foo" || pub.ID() != "hsm" {
+		t.Error("unexpected public key params:", pub.Parameters())
+	}
+
+	_, err = NewSignerKey(nil, nil)
+	if err == nil {
+		t.Error("expected error on creating signer key without signer")
+	}
+}
@@ -0,0 +1,299 @@
+package jwk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxStrictDepth limits the nesting of JSON values in strict mode.
+const maxStrictDepth = 32
+
+// Members checked in strict mode, by expected JSON type.
+var (
+	stringMembers = []string{"kty", "use", "alg", "kid", "crv", "x5u", "x5t", "x5t#S256"}
+	octetMembers  = []string{"n", "e", "d", "p", "q", "dp", "dq", "qi", "k", "x", "y"}
+	arrayMembers  = []string{"key_ops", "x5c"}
+	otherMembers  = []string{"oth"}
+)
+
+// ParseStrict parses data as a JSON Web Key like Parse, but rejects input
+// that Parse silently accepts:
+//
+//   - duplicate members, of which encoding/json keeps the last one,
+//     including names differing only in case, which it treats as equal;
+//   - known members spelled with a different case, such as "K" for "k";
+//   - known members of the wrong JSON type, including null;
+//   - kty values differing from a known key type only in case;
+//   - empty strings for kty, crv and base64url-encoded members;
+//   - data following the JWK.
+func ParseStrict(data []byte) (Key, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	v, err := decodeStrict(dec, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEOF(dec); err != nil {
+		return nil, err
+	}
+
+	return parseStrictValue(v)
+}
+
+// ParseSetStrict parses data as a JSON Web Key Set, applying the checks
+// of ParseStrict to the set and to each of its keys. The keys member is
+// required.
+func ParseSetStrict(data []byte) (*Set, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var set *Set
+	seen := make(map[string]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, parseError("", fmt.Errorf("%w: %w", ErrMalformed, err))
+		}
+		name := tok.(string)
+		if err := checkName(seen, name, "keys"); err != nil {
+			return nil, err
+		}
+
+		if name != "keys" {
+			if _, err := decodeStrict(dec, 1); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := expectDelim(dec, '['); err != nil {
+			return nil, parseError("keys", fmt.Errorf("%w: not an array", ErrInvalidMember))
+		}
+		set = &Set{Keys: []Key{}}
+		for i := 0; dec.More(); i++ {
+			v, err := decodeStrict(dec, 2)
+			if err != nil {
+				return nil, inSet(err, i)
+			}
+			key, err := parseStrictValue(v)
+			if err != nil {
+				return nil, inSet(err, i)
+			}
+			set.Keys = append(set.Keys, key)
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+	if err := checkEOF(dec); err != nil {
+		return nil, err
+	}
+
+	if set == nil {
+		return nil, parseError("keys", ErrMissingMember)
+	}
+
+	return set, nil
+}
+
+// expectDelim reads the next token, which must be delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return parseError("", fmt.Errorf("%w: %w", ErrMalformed, err))
+	}
+	if tok != delim {
+		return parseError("", fmt.Errorf("%w: expected %v", ErrMalformed, delim))
+	}
+
+	return nil
+}
+
+// parseStrictValue checks the members of a decoded JWK and parses it.
+func parseStrictValue(v interface{}) (Key, error) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, parseError("", fmt.Errorf("%w: not a JSON object", ErrMalformed))
+	}
+	if err := checkMembers(obj); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, parseError("", err)
+	}
+
+	return Parse(data)
+}
+
+// checkMembers checks the JSON types and values of known members.
+func checkMembers(obj map[string]interface{}) error {
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, members := range [][]string{stringMembers, octetMembers, arrayMembers, otherMembers} {
+			if err := checkSpelling(name, members...); err != nil {
+				return err
+			}
+		}
+
+		v := obj[name]
+		switch {
+		case containsString(stringMembers, name), containsString(octetMembers, name):
+			s, ok := v.(string)
+			if !ok {
+				return parseError(name, fmt.Errorf("%w: not a string", ErrInvalidMember))
+			}
+			if s == "" && (name == "kty" || name == "crv" || containsString(octetMembers, name)) {
+				return parseError(name, fmt.Errorf("%w: empty string", ErrInvalidMember))
+			}
+		case containsString(arrayMembers, name):
+			arr, ok := v.([]interface{})
+			if !ok {
+				return parseError(name, fmt.Errorf("%w: not an array", ErrInvalidMember))
+			}
+			for _, item := range arr {
+				if _, ok := item.(string); !ok {
+					return parseError(name, fmt.Errorf("%w: not an array of strings", ErrInvalidMember))
+				}
+			}
+		}
+	}
+
+	kty, _ := obj["kty"].(string)
+	for _, t := range []string{TypeEC, TypeRSA, TypeOCT, TypeOKP} {
+		if kty != t && strings.EqualFold(kty, t) {
+			return parseError("kty", fmt.Errorf("%w: %q must be %q", ErrInvalidMember, kty, t))
+		}
+	}
+
+	return nil
+}
+
+// decodeStrict decodes the next JSON value, rejecting duplicate members.
+// Objects are decoded as map[string]interface{}, arrays as
+// []interface{} and numbers as json.Number.
+func decodeStrict(dec *json.Decoder, depth int) (interface{}, error) {
+	if depth > maxStrictDepth {
+		return nil, parseError("", fmt.Errorf("%w: nesting too deep", ErrMalformed))
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, parseError("", fmt.Errorf("%w: %w", ErrMalformed, err))
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		obj := make(map[string]interface{})
+		seen := make(map[string]bool)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, parseError("", fmt.Errorf("%w: %w", ErrMalformed, err))
+			}
+			name := tok.(string)
+			if err := checkName(seen, name); err != nil {
+				return nil, err
+			}
+
+			v, err := decodeStrict(dec, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = v
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, parseError("", fmt.Errorf("%w: %w", ErrMalformed, err))
+		}
+		return obj, nil
+	case json.Delim('['):
+		arr := []interface{}{}
+		for dec.More() {
+			v, err := decodeStrict(dec, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, parseError("", fmt.Errorf("%w: %w", ErrMalformed, err))
+		}
+		return arr, nil
+	default:
+		return tok, nil
+	}
+}
+
+// checkName records the member name in seen, rejecting duplicates and
+// spellings of the known members differing only in case. As
+// encoding/json matches member names case-insensitively, names are
+// compared after case folding.
+func checkName(seen map[string]bool, name string, known ...string) error {
+	folded := foldName(name)
+	if seen[folded] {
+		return parseError(name, ErrDuplicateMember)
+	}
+	seen[folded] = true
+
+	return checkSpelling(name, known...)
+}
+
+// checkSpelling rejects name if it differs from one of the known member
+// names only in case.
+func checkSpelling(name string, known ...string) error {
+	for _, k := range known {
+		if name != k && foldName(name) == foldName(k) {
+			return parseError(name, fmt.Errorf("%w: must be spelled %q", ErrInvalidMember, k))
+		}
+	}
+
+	return nil
+}
+
+// foldName returns name with each rune replaced by the smallest rune it
+// is equal to under Unicode case folding, so that foldName(a) ==
+// foldName(b) if and only if strings.EqualFold(a, b).
+func foldName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		min := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < min {
+				min = f
+			}
+		}
+		b.WriteRune(min)
+	}
+
+	return b.String()
+}
+
+// checkEOF ensures there is nothing but whitespace left in dec.
+func checkEOF(dec *json.Decoder) error {
+	_, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return parseError("", ErrTrailingData)
+}
@@ -0,0 +1,105 @@
+package jwk
+
+import (
+	"errors"
+	"testing"
+)
+
+const strictTestKey = `{"kty":"EC","crv":"P-256",
+  "x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",
+  "y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM",
+  "kid":"1"}`
+
+func TestParseStrict(t *testing.T) {
+	key, err := ParseStrict([]byte(strictTestKey + "\n"))
+	if err != nil {
+		t.Fatal("failed to parse valid key:", err)
+	}
+
+	lenient, _ := Parse([]byte(strictTestKey))
+	if !EqualWithParams(key, lenient) {
+		t.Error("strict and lenient parsing gave different keys")
+	}
+}
+
+func TestParseStrictInvalid(t *testing.T) {
+	x := `"x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4"`
+	y := `"y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"`
+
+	cases := []struct {
+		jwk    string
+		member string
+		err    error
+	}{
+		{`{"kty":"EC","crv":"P-256",` + x + `,` + y + `,"x":"AAAA"}`, "x", ErrDuplicateMember},
+		{`{"kty":"EC","kty":"RSA"}`, "kty", ErrDuplicateMember},
+		{`{"kty":"oct","k":"AQAB","K":"AAAA"}`, "K", ErrDuplicateMember},
+		{`{"KTY":"EC","kty":"oct","k":"AQAB"}`, "kty", ErrDuplicateMember},
+		{`{"kty":"oct","k":"AQAB","\u212a":"AAAA"}`, "\u212a", ErrDuplicateMember},
+		{`{"kty":"oct","K":"AQAB"}`, "K", ErrInvalidMember},
+		{`{"Kty":"oct","k":"AQAB"}`, "Kty", ErrInvalidMember},
+		{`{"kty":"EC","crv":"P-256",` + x + `,` + y + `,"KEY_OPS":["verify"]}`, "KEY_OPS", ErrInvalidMember},
+		{`{"kty":"EC","crv":"P-256",` + x + `,` + y + `,"kid":null}`, "kid", ErrInvalidMember},
+		{`{"kty":"EC","crv":"P-256",` + x + `,` + y + `,"kid":1}`, "kid", ErrInvalidMember},
+		{`{"kty":"EC","crv":"P-256",` + x + `,` + y + `,"key_ops":"verify"}`, "key_ops", ErrInvalidMember},
+		{`{"kty":"EC","crv":"P-256",` + x + `,` + y + `,"x5c":[1]}`, "x5c", ErrInvalidMember},
+		{`{"kty":"ec","crv":"P-256",` + x + `,` + y + `}`, "kty", ErrInvalidMember},
+		{`{"kty":"OCT","k":"AAAA"}`, "kty", ErrInvalidMember},
+		{`{"kty":"oct","k":""}`, "k", ErrInvalidMember},
+		{`{"kty":"EC","crv":"",` + x + `,` + y + `}`, "crv", ErrInvalidMember},
+		{`{"kty":"oct","k":"AAAA"} {}`, "", ErrTrailingData},
+		{`{"kty":"oct","k":"AAAA"}}`, "", ErrTrailingData},
+		{`[]`, "", ErrMalformed},
+		{`{"kty":"oct",`, "", ErrMalformed},
+	}
+
+	for _, c := range cases {
+		_, err := ParseStrict([]byte(c.jwk))
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Member != c.member || !errors.Is(err, c.err) {
+			t.Errorf("%s: unexpected error %v", c.jwk, err)
+		}
+	}
+
+	// The lenient parser takes the last duplicate.
+	if _, err := Parse([]byte(`{"kty":"oct","k":"AAAA","k":"AQAB"}`)); err != nil {
+		t.Error("lenient parser rejected duplicate member:", err)
+	}
+}
+
+func TestParseSetStrict(t *testing.T) {
+	set, err := ParseSetStrict([]byte(`{"keys":[` + strictTestKey + `,{"kty":"oct","k":"AAAA"}]}`))
+	if err != nil {
+		t.Fatal("failed to parse valid set:", err)
+	}
+	if len(set.Keys) != 2 {
+		t.Error("unexpected number of keys:", len(set.Keys))
+	}
+
+	cases := []struct {
+		jwks  string
+		index int
+		err   error
+	}{
+		{`{"keys":[{"kty":"oct","k":"AAAA"},{"kty":"oct","k":"AAAA","k":"AAAA"}]}`, 1, ErrDuplicateMember},
+		{`{"keys":[{"kty":"oct","k":"AAAA"},{"kty":"oct","k":""}]}`, 1, ErrInvalidMember},
+		{`{"keys":[],"keys":[]}`, -1, ErrDuplicateMember},
+		{`{"keys":[],"Keys":[]}`, -1, ErrDuplicateMember},
+		{`{"Keys":[]}`, -1, ErrInvalidMember},
+		{`{"keys":[{"kty":"oct","K":"AAAA"}]}`, 0, ErrInvalidMember},
+		{`{"keys":{}}`, -1, ErrInvalidMember},
+		{`{}`, -1, ErrMissingMember},
+		{`[]`, -1, ErrMalformed},
+		{`{"keys":[]} []`, -1, ErrTrailingData},
+	}
+
+	for _, c := range cases {
+		_, err := ParseSetStrict([]byte(c.jwks))
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Index != c.index || !errors.Is(err, c.err) {
+			t.Errorf("%s: unexpected error %v", c.jwks, err)
+		}
+	}
+}
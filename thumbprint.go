@@ -0,0 +1,82 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+// Thumbprint computes the JWK Thumbprint of the key as defined in RFC
+// 7638, using the hash function h. Only the required members of the key
+// are hashed, so a private key and its public counterpart share the
+// same thumbprint.
+func Thumbprint(key Key, h crypto.Hash) ([]byte, error) {
+	if !h.Available() {
+		return nil, errors.New("jwk: hash function not available")
+	}
+
+	data, err := thumbprintInput(key.CryptoKey())
+	if err != nil {
+		return nil, err
+	}
+
+	hash := h.New()
+	hash.Write(data)
+
+	return hash.Sum(nil), nil
+}
+
+// thumbprintInput returns the required members of the key, in
+// lexicographic order and without whitespace.
+func thumbprintInput(key CryptoKey) ([]byte, error) {
+	enc := base64.RawURLEncoding.EncodeToString
+
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		pub, err := k.ECDH()
+		if err != nil {
+			return nil, ErrInvalidCryptoKey
+		}
+		point := pub.Bytes()
+		size := (len(point) - 1) / 2
+
+		return json.Marshal(struct {
+			CRV     string `json:"crv"`
+			KeyType string `json:"kty"`
+			X       string `json:"x"`
+			Y       string `json:"y"`
+		}{k.Curve.Params().Name, TypeEC, enc(point[1 : 1+size]), enc(point[1+size:])})
+	case *ecdsa.PrivateKey:
+		return thumbprintInput(&k.PublicKey)
+	case *rsa.PublicKey:
+		return json.Marshal(struct {
+			E       string `json:"e"`
+			KeyType string `json:"kty"`
+			N       string `json:"n"`
+		}{enc(big.NewInt(int64(k.E)).Bytes()), TypeRSA, enc(k.N.Bytes())})
+	case *rsa.PrivateKey:
+		return thumbprintInput(&k.PublicKey)
+	case ed25519.PublicKey:
+		return json.Marshal(struct {
+			CRV     string `json:"crv"`
+			KeyType string `json:"kty"`
+			X       string `json:"x"`
+		}{CurveEd25519, TypeOKP, enc(k)})
+	case ed25519.PrivateKey:
+		return thumbprintInput(k.Public())
+	case []byte:
+		return json.Marshal(struct {
+			K       string `json:"k"`
+			KeyType string `json:"kty"`
+		}{enc(k), TypeOCT})
+	case crypto.Signer:
+		return thumbprintInput(k.Public())
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+}
@@ -0,0 +1,63 @@
+package jwk
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	"encoding/base64"
+	"testing"
+)
+
+func TestThumbprint(t *testing.T) {
+	cases := []struct {
+		jwk        string
+		thumbprint string
+	}{
+		// RFC 7638, Section 3.1.
+		{
+			`{"kty":"RSA",
+			"n": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+			"e":"AQAB",
+			"alg":"RS256",
+			"kid":"2011-04-29"}`,
+			"NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+		},
+		// RFC 8037, Appendix A.3.
+		{
+			rfc8037PrivateKey,
+			"kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k",
+		},
+	}
+
+	for _, c := range cases {
+		key, err := Parse([]byte(c.jwk))
+		if err != nil {
+			t.Fatal("failed to parse key:", err)
+		}
+
+		tp, err := Thumbprint(key, crypto.SHA256)
+		if err != nil {
+			t.Fatal("failed to compute thumbprint:", err)
+		}
+
+		if base64.RawURLEncoding.EncodeToString(tp) != c.thumbprint {
+			t.Errorf("unexpected thumbprint for %s key", key.Type())
+		}
+	}
+}
+
+func TestThumbprintPublicKey(t *testing.T) {
+	priv, _ := NewECDSAPrivateKey(ecdsaTestKeyP256, nil)
+
+	a, err := Thumbprint(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Thumbprint(priv.PublicKey(), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(a) != string(b) {
+		t.Error("private and public keys have different thumbprints")
+	}
+}
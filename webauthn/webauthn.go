@@ -0,0 +1,192 @@
+// Package webauthn extracts credential public keys from WebAuthn
+// authenticator data and attestation objects, as defined in the W3C Web
+// Authentication specification, Section 6.
+//
+// Attestation statements are not verified.
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ericyan/jwk"
+	"github.com/ericyan/jwk/cose"
+	"github.com/ericyan/jwk/internal/cbor"
+)
+
+// Authenticator data flags.
+const (
+	FlagUserPresent            = 0x01
+	FlagUserVerified           = 0x04
+	FlagBackupEligible         = 0x08
+	FlagBackupState            = 0x10
+	FlagAttestedCredentialData = 0x40
+	FlagExtensionData          = 0x80
+)
+
+// Sizes of the fixed-length fields in authenticator data.
+const (
+	rpIDHashSize = 32
+	aaguidSize   = 16
+	minAuthData  = rpIDHashSize + 1 + 4
+)
+
+// AuthenticatorData represents the authenticator data structure.
+type AuthenticatorData struct {
+	RPIDHash  []byte
+	Flags     byte
+	SignCount uint32
+
+	// Attested credential data, present only if FlagAttestedCredentialData
+	// is set.
+	AAGUID       []byte
+	CredentialID []byte
+	PublicKey    jwk.Key
+
+	// Extensions holds the CBOR-encoded extension outputs, present only if
+	// FlagExtensionData is set.
+	Extensions []byte
+}
+
+// ParseAuthenticatorData parses the authenticator data. The credential
+// public key, if present, must use one of the ES256, RS256 or EdDSA
+// algorithms; its COSE alg is kept as the JWK alg, and the base64url
+// encoded credential ID is used as kid unless the key has one.
+func ParseAuthenticatorData(data []byte) (*AuthenticatorData, error) {
+	if len(data) < minAuthData {
+		return nil, errors.New("webauthn: authenticator data too short")
+	}
+
+	ad := &AuthenticatorData{
+		RPIDHash:  append([]byte{}, data[:rpIDHashSize]...),
+		Flags:     data[rpIDHashSize],
+		SignCount: binary.BigEndian.Uint32(data[rpIDHashSize+1:]),
+	}
+	rest := data[minAuthData:]
+
+	if ad.Flags&FlagAttestedCredentialData != 0 {
+		if len(rest) < aaguidSize+2 {
+			return nil, errors.New("webauthn: attested credential data too short")
+		}
+		ad.AAGUID = append([]byte{}, rest[:aaguidSize]...)
+
+		n := int(binary.BigEndian.Uint16(rest[aaguidSize:]))
+		rest = rest[aaguidSize+2:]
+		if n == 0 || len(rest) < n {
+			return nil, errors.New("webauthn: invalid credential ID length")
+		}
+		ad.CredentialID = append([]byte{}, rest[:n]...)
+
+		key, r, err := cose.Decode(rest[n:])
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPublicKey(key); err != nil {
+			return nil, err
+		}
+		if key.ID() == "" {
+			key.Parameters().KeyID = base64.RawURLEncoding.EncodeToString(ad.CredentialID)
+		}
+		ad.PublicKey = key
+		rest = r
+	}
+
+	if ad.Flags&FlagExtensionData != 0 {
+		if _, r, err := cbor.Decode(rest); err != nil {
+			return nil, err
+		} else if len(r) != 0 {
+			return nil, errors.New("webauthn: trailing data after extensions")
+		}
+		ad.Extensions = append([]byte{}, rest...)
+		rest = nil
+	}
+
+	if len(rest) != 0 {
+		return nil, errors.New("webauthn: trailing data in authenticator data")
+	}
+
+	return ad, nil
+}
+
+// checkPublicKey ensures key is a public key of a supported algorithm.
+func checkPublicKey(key jwk.Key) error {
+	if key.IsPrivate() {
+		return errors.New("webauthn: credential key contains private key material")
+	}
+
+	switch key.Alg() {
+	case "ES256":
+		pub, ok := key.CryptoKey().(*ecdsa.PublicKey)
+		if !ok || pub.Curve != elliptic.P256() {
+			return errors.New("webauthn: ES256 requires a P-256 key")
+		}
+	case "RS256":
+		if key.Type() != jwk.TypeRSA {
+			return errors.New("webauthn: RS256 requires an RSA key")
+		}
+	case "EdDSA":
+		if key.Type() != jwk.TypeOKP {
+			return errors.New("webauthn: EdDSA requires an OKP key")
+		}
+	case "":
+		return errors.New("webauthn: credential key is missing alg")
+	default:
+		return errors.New("webauthn: unsupported credential key algorithm")
+	}
+
+	return nil
+}
+
+// AttestationObject represents an attestation object.
+type AttestationObject struct {
+	Format   string
+	AuthData *AuthenticatorData
+}
+
+// ParseAttestationObject parses the CBOR-encoded attestation object.
+func ParseAttestationObject(data []byte) (*AttestationObject, error) {
+	v, err := cbor.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := v.(cbor.Map)
+	if !ok {
+		return nil, errors.New("webauthn: attestation object is not a map")
+	}
+
+	format, ok := m["fmt"].(string)
+	if !ok {
+		return nil, errors.New("webauthn: missing or invalid fmt")
+	}
+	if _, ok := m["attStmt"].(cbor.Map); !ok {
+		return nil, errors.New("webauthn: missing or invalid attStmt")
+	}
+	raw, ok := m["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("webauthn: missing or invalid authData")
+	}
+
+	ad, err := ParseAuthenticatorData(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttestationObject{format, ad}, nil
+}
+
+// PublicKey returns the credential public key in the attestation object.
+func PublicKey(attestationObject []byte) (jwk.Key, error) {
+	obj, err := ParseAttestationObject(attestationObject)
+	if err != nil {
+		return nil, err
+	}
+	if obj.AuthData.PublicKey == nil {
+		return nil, errors.New("webauthn: no attested credential data")
+	}
+
+	return obj.AuthData.PublicKey, nil
+}
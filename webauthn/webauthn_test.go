@@ -0,0 +1,167 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ericyan/jwk"
+	"github.com/ericyan/jwk/cose"
+	"github.com/ericyan/jwk/internal/cbor"
+)
+
+var credentialID = []byte{0xde, 0xad, 0xbe, 0xef}
+
+func authData(flags byte, key []byte, ext []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(bytes.Repeat([]byte{0x49}, rpIDHashSize))
+	buf.WriteByte(flags)
+	buf.Write(binary.BigEndian.AppendUint32(nil, 42))
+	if key != nil {
+		buf.Write(make([]byte, aaguidSize))
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(len(credentialID))))
+		buf.Write(credentialID)
+		buf.Write(key)
+	}
+	buf.Write(ext)
+
+	return buf.Bytes()
+}
+
+func coseKey(t *testing.T, ck jwk.CryptoKey, alg string) []byte {
+	key, err := jwk.New(ck, &jwk.Params{Algorithm: alg})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := cose.MarshalPrivate(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestPublicKey(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	edPub, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	cases := []struct {
+		key jwk.CryptoKey
+		alg string
+	}{
+		{&ecKey.PublicKey, "ES256"},
+		{&rsaKey.PublicKey, "RS256"},
+		{edPub, "EdDSA"},
+	}
+
+	for _, c := range cases {
+		ad := authData(FlagUserPresent|FlagAttestedCredentialData, coseKey(t, c.key, c.alg), nil)
+		obj, err := cbor.Marshal(cbor.Map{"fmt": "none", "attStmt": cbor.Map{}, "authData": ad})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := PublicKey(obj)
+		if err != nil {
+			t.Errorf("%s: failed to get public key: %s", c.alg, err)
+			continue
+		}
+
+		expected, _ := jwk.New(c.key, nil)
+		if !jwk.Equal(key, expected) {
+			t.Errorf("%s: unexpected public key", c.alg)
+		}
+		if key.Alg() != c.alg {
+			t.Errorf("%s: unexpected alg %q", c.alg, key.Alg())
+		}
+		if key.ID() != "3q2-7w" {
+			t.Errorf("%s: unexpected kid %q", c.alg, key.ID())
+		}
+	}
+}
+
+func TestParseAuthenticatorData(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ext, _ := cbor.Marshal(cbor.Map{"credProtect": int64(2)})
+
+	data := authData(FlagUserPresent|FlagUserVerified|FlagAttestedCredentialData|FlagExtensionData,
+		coseKey(t, &ecKey.PublicKey, "ES256"), ext)
+
+	ad, err := ParseAuthenticatorData(data)
+	if err != nil {
+		t.Fatal("failed to parse authenticator data:", err)
+	}
+
+	if ad.SignCount != 42 || ad.Flags&FlagUserVerified == 0 {
+		t.Error("unexpected authenticator data:", ad)
+	}
+	if !bytes.Equal(ad.CredentialID, credentialID) || len(ad.AAGUID) != aaguidSize {
+		t.Error("unexpected attested credential data")
+	}
+	if !bytes.Equal(ad.Extensions, ext) {
+		t.Error("unexpected extensions")
+	}
+
+	// Assertions carry no attested credential data.
+	ad, err = ParseAuthenticatorData(authData(FlagUserPresent, nil, nil))
+	if err != nil {
+		t.Fatal("failed to parse authenticator data:", err)
+	}
+	if ad.PublicKey != nil {
+		t.Error("unexpected public key")
+	}
+}
+
+func TestParseAuthenticatorDataInvalid(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edPriv, _ := ed25519.GenerateKey(rand.Reader)
+
+	cases := map[string][]byte{
+		"too short":      make([]byte, minAuthData-1),
+		"trailing data":  append(authData(FlagUserPresent, nil, nil), 0),
+		"missing alg":    authData(FlagAttestedCredentialData, coseKey(t, &ecKey.PublicKey, ""), nil),
+		"wrong curve":    authData(FlagAttestedCredentialData, coseKey(t, &ecKey.PublicKey, "ES256"), nil),
+		"alg mismatch":   authData(FlagAttestedCredentialData, coseKey(t, &rsaKey.PublicKey, "EdDSA"), nil),
+		"unsupported":    authData(FlagAttestedCredentialData, coseKey(t, &ecKey.PublicKey, "ES384"), nil),
+		"private key":    authData(FlagAttestedCredentialData, coseKey(t, edPriv, "EdDSA"), nil),
+		"truncated key":  authData(FlagAttestedCredentialData, coseKey(t, &rsaKey.PublicKey, "RS256")[:10], nil),
+		"missing key":    authData(FlagAttestedCredentialData, nil, nil),
+		"bad extensions": authData(FlagExtensionData, nil, []byte{0xa1}),
+	}
+
+	for name, data := range cases {
+		if _, err := ParseAuthenticatorData(data); err == nil {
+			t.Errorf("%s: expected error on parsing invalid authenticator data", name)
+		}
+	}
+}
+
+func TestParseAttestationObjectInvalid(t *testing.T) {
+	ad := authData(FlagUserPresent, nil, nil)
+
+	for _, obj := range []interface{}{
+		[]interface{}{},
+		cbor.Map{"attStmt": cbor.Map{}, "authData": ad},
+		cbor.Map{"fmt": "none", "authData": ad},
+		cbor.Map{"fmt": "none", "attStmt": cbor.Map{}},
+		cbor.Map{"fmt": "none", "attStmt": cbor.Map{}, "authData": "text"},
+	} {
+		data, _ := cbor.Marshal(obj)
+		if _, err := ParseAttestationObject(data); err == nil {
+			t.Errorf("expected error on parsing invalid attestation object %v", obj)
+		}
+	}
+
+	data, _ := cbor.Marshal(cbor.Map{"fmt": "none", "attStmt": cbor.Map{}, "authData": ad})
+	if _, err := PublicKey(data); err == nil {
+		t.Error("expected error on attestation object without credential")
+	}
+}